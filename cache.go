@@ -0,0 +1,493 @@
+package yadisk
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Time a cached resource is served when CacheOptions.TTL is zero.
+const DefaultCacheTTL = time.Minute
+
+// Options of NewCachedDisk.
+type CacheOptions struct {
+	// Time a response is served from the cache, DefaultCacheTTL by default.
+	TTL time.Duration
+	// Clock of the TTL, time.Now by default.
+	Now func() time.Time
+}
+
+// CachedDisk is a YaDisk caching the responses of GetResource, the metadata of resources and the pages of folders.
+//
+// Mutating calls invalidate the cached resources at the affected paths, below them and their parents, and again
+// once their asynchronous operation finished, if its status is requested before the TTL passes. Identical concurrent
+// requests are coalesced into one. Uploads made through links obtained elsewhere are not seen, call Invalidate for them.
+// Returned resources share their custom properties with the cache, treat them as read-only.
+type CachedDisk struct {
+	yad YaDisk
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[Path]map[string]*cacheEntry
+	calls   map[string]*cacheCall
+	// Bumped by every invalidation, responses of requests started before one are not cached.
+	generation int64
+	// Paths to invalidate when an upload link or an operation finishes.
+	uploads    map[string]*cachePending
+	operations map[string]*cachePending
+}
+
+type cacheEntry struct {
+	r       *Resource
+	expires time.Time
+}
+
+// cachePending is an upload or an operation not known to be finished, dropped once the TTL passes without news of it.
+type cachePending struct {
+	paths   []Path
+	expires time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	r    *Resource
+	e    error
+}
+
+var _ YaDiskWithOptions = (*CachedDisk)(nil)
+
+// NewCachedDisk returns yad with a cache of resource metadata.
+func NewCachedDisk(yad YaDisk, opts *CacheOptions) *CachedDisk {
+	c := &CachedDisk{
+		yad:        yad,
+		ttl:        DefaultCacheTTL,
+		now:        time.Now,
+		entries:    make(map[Path]map[string]*cacheEntry),
+		calls:      make(map[string]*cacheCall),
+		uploads:    make(map[string]*cachePending),
+		operations: make(map[string]*cachePending),
+	}
+	if opts != nil {
+		if opts.TTL > 0 {
+			c.ttl = opts.TTL
+		}
+		if opts.Now != nil {
+			c.now = opts.Now
+		}
+	}
+	return c
+}
+
+// Invalidate drops the cached resources at the paths, below them and their parents.
+func (c *CachedDisk) Invalidate(paths ...Path) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(paths...)
+}
+
+// Purge drops the whole cache.
+func (c *CachedDisk) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[Path]map[string]*cacheEntry)
+	c.calls = make(map[string]*cacheCall)
+	c.generation++
+}
+
+func (c *CachedDisk) invalidate(paths ...Path) {
+	c.generation++
+	for _, p := range paths {
+		p = p.Clean()
+		for cached := range c.entries {
+			if cached.Within(p) || p.Within(cached) {
+				delete(c.entries, cached)
+			}
+		}
+	}
+	// Requests in flight may have read the old state, later ones must not join them.
+	c.calls = make(map[string]*cacheCall)
+}
+
+// mutated invalidates the paths now and, if the link is an asynchronous operation, when it finishes.
+func (c *CachedDisk) mutated(l *Link, paths ...Path) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(paths...)
+	if id, ok := OperationID(l); ok {
+		c.addPending(c.operations, id, paths...)
+	}
+}
+
+// addPending adds the paths to invalidate when the upload or operation finishes and drops the expired ones.
+func (c *CachedDisk) addPending(m map[string]*cachePending, id string, paths ...Path) {
+	now := c.now()
+	for k, p := range m {
+		if !now.Before(p.expires) {
+			delete(m, k)
+		}
+	}
+	p := m[id]
+	if p == nil {
+		p = new(cachePending)
+		m[id] = p
+	}
+	p.paths = append(p.paths, paths...)
+	p.expires = now.Add(c.ttl)
+}
+
+// pending returns the upload or operation, nil if it is unknown or expired.
+func (c *CachedDisk) pending(m map[string]*cachePending, id string) *cachePending {
+	p := m[id]
+	if p != nil && !c.now().Before(p.expires) {
+		delete(m, id)
+		return nil
+	}
+	return p
+}
+
+// cacheKey identifies the request of a resource with options.
+func cacheKey(p Path, opts *ResourceOptions) string {
+	if opts == nil {
+		return string(p)
+	}
+	return fmt.Sprintf("%s?%s&%s&%s&%t&%s&%s", p, strings.Join(opts.Fields, ","),
+		optionalInt(opts.Limit), optionalInt(opts.Offset), opts.PreviewCrop, opts.PreviewSize, opts.Sort)
+}
+
+// optionalInt formats p for a cache key, empty if it is nil.
+func optionalInt(p *int) string {
+	if p == nil {
+		return ""
+	}
+	return strconv.Itoa(*p)
+}
+
+// copyResource returns a copy of r with its own list of items.
+func copyResource(r *Resource) *Resource {
+	cp := *r
+	cp.Embedded.Items = append([]Resource(nil), r.Embedded.Items...)
+	return &cp
+}
+
+// Get user disk meta information.
+func (c *CachedDisk) GetDisk(fields []string) (*Disk, error) {
+	return c.yad.GetDisk(fields)
+}
+
+// Empty trash.
+func (c *CachedDisk) ClearTrash(fields []string, forceAsync bool, path string) (*Link, error) {
+	return c.yad.ClearTrash(fields, forceAsync, path)
+}
+
+// Empty trash.
+func (c *CachedDisk) ClearTrashWithOptions(opts *ClearTrashOptions) (*Link, error) {
+	return ClearTrashWithOptions(c.yad, opts)
+}
+
+// Get the contents of the Trash.
+func (c *CachedDisk) GetTrashResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*TrashResource, error) {
+	return c.yad.GetTrashResource(path, fields, limit, offset, previewCrop, previewSize, sort)
+}
+
+// Get the contents of the Trash.
+func (c *CachedDisk) GetTrashResourceWithOptions(path string, opts *ResourceOptions) (*TrashResource, error) {
+	return GetTrashResourceWithOptions(c.yad, path, opts)
+}
+
+// Recover Resource from Trash.
+//
+// The origin path of the resource is requested first to invalidate it, the whole cache is dropped if that fails.
+func (c *CachedDisk) RestoreFromTrash(path string, fields []string, forceAsync bool, name string, overwrite bool) (*Link, error) {
+	return c.RestoreFromTrashWithOptions(path, &RestoreFromTrashOptions{Fields: fields, ForceAsync: forceAsync, Name: name, Overwrite: overwrite})
+}
+
+// Recover Resource from Trash.
+func (c *CachedDisk) RestoreFromTrashWithOptions(path string, opts *RestoreFromTrashOptions) (*Link, error) {
+	item, e := GetTrashResourceWithOptions(c.yad, path, &ResourceOptions{Fields: []string{"origin_path"}, Limit: Int(1)})
+	l, e2 := RestoreFromTrashWithOptions(c.yad, path, opts)
+	if e != nil || item.OriginPath == "" {
+		c.Purge()
+		return l, e2
+	}
+	origin := Path(item.OriginPath)
+	if opts != nil && opts.Name != "" {
+		origin = origin.Dir().Join(opts.Name)
+	}
+	c.mutated(l, origin)
+	return l, e2
+}
+
+// Delete file or folder.
+func (c *CachedDisk) DeleteResource(path string, fields []string, forceAsync bool, md5 string, permanently bool) (*Link, error) {
+	return c.DeleteResourceWithOptions(path, &DeleteOptions{Fields: fields, ForceAsync: forceAsync, Md5: md5, Permanently: permanently})
+}
+
+// Delete file or folder.
+func (c *CachedDisk) DeleteResourceWithOptions(path string, opts *DeleteOptions) (*Link, error) {
+	l, e := DeleteResourceWithOptions(c.yad, path, opts)
+	c.mutated(l, Path(path))
+	return l, e
+}
+
+// Get meta information about a file or directory.
+func (c *CachedDisk) GetResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*Resource, error) {
+	return c.GetResourceWithOptions(path, &ResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get meta information about a file or directory.
+//
+// Responses are served from the cache until the TTL passes or the path is invalidated.
+func (c *CachedDisk) GetResourceWithOptions(path string, opts *ResourceOptions) (*Resource, error) {
+	p := Path(path).Clean()
+	key := cacheKey(p, opts)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[p][key]; ok {
+		if c.now().Before(entry.expires) {
+			c.mu.Unlock()
+			return copyResource(entry.r), nil
+		}
+		delete(c.entries[p], key)
+	}
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		if call.e != nil {
+			return nil, call.e
+		}
+		return copyResource(call.r), nil
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	call.r, call.e = GetResourceWithOptions(c.yad, path, opts)
+
+	c.mu.Lock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	if call.e == nil && generation == c.generation {
+		if c.entries[p] == nil {
+			c.entries[p] = make(map[string]*cacheEntry)
+		}
+		c.entries[p][key] = &cacheEntry{r: call.r, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	if call.e != nil {
+		return nil, call.e
+	}
+	return copyResource(call.r), nil
+}
+
+// Create directory.
+func (c *CachedDisk) CreateResource(path string, fields []string) (*Link, error) {
+	l, e := c.yad.CreateResource(path, fields)
+	c.mutated(nil, Path(path))
+	return l, e
+}
+
+// Update User Resource Data.
+func (c *CachedDisk) UpdateResource(path string, fields []string, body *ResourcePatch) (*Resource, error) {
+	r, e := c.yad.UpdateResource(path, fields, body)
+	c.mutated(nil, Path(path))
+	return r, e
+}
+
+// Create a copy of the file or folder.
+func (c *CachedDisk) CopyResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*Link, error) {
+	return c.CopyResourceWithOptions(from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Create a copy of the file or folder.
+func (c *CachedDisk) CopyResourceWithOptions(from string, path string, opts *TransferOptions) (*Link, error) {
+	l, e := CopyResourceWithOptions(c.yad, from, path, opts)
+	c.mutated(l, Path(path))
+	return l, e
+}
+
+// Move a file or folder.
+func (c *CachedDisk) MoveResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*Link, error) {
+	return c.MoveResourceWithOptions(from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Move a file or folder.
+func (c *CachedDisk) MoveResourceWithOptions(from string, path string, opts *TransferOptions) (*Link, error) {
+	l, e := MoveResourceWithOptions(c.yad, from, path, opts)
+	c.mutated(l, Path(from), Path(path))
+	return l, e
+}
+
+// Get link to download file.
+func (c *CachedDisk) GetResourceDownloadLink(path string, fields []string) (*Link, error) {
+	return c.yad.GetResourceDownloadLink(path, fields)
+}
+
+// Get file list sorted by name.
+func (c *CachedDisk) GetFlatFilesList(fields []string, limit int, mediaType string, offset int, previewCrop bool, previewSize string, sort string) (*FilesResourceList, error) {
+	return c.yad.GetFlatFilesList(fields, limit, mediaType, offset, previewCrop, previewSize, sort)
+}
+
+// Get file list sorted by name.
+func (c *CachedDisk) GetFlatFilesListWithOptions(opts *FilesListOptions) (*FilesResourceList, error) {
+	return GetFlatFilesListWithOptions(c.yad, opts)
+}
+
+// Get a list of files ordered by download date.
+func (c *CachedDisk) GetLastUploadedFilesList(fields []string, limit int, mediaType string, previewCrop bool, previewSize string) (*LastUploadedResourceList, error) {
+	return c.yad.GetLastUploadedFilesList(fields, limit, mediaType, previewCrop, previewSize)
+}
+
+// Get a list of files ordered by download date.
+func (c *CachedDisk) GetLastUploadedFilesListWithOptions(opts *LastUploadedOptions) (*LastUploadedResourceList, error) {
+	return GetLastUploadedFilesListWithOptions(c.yad, opts)
+}
+
+// Get a list of published resources.
+func (c *CachedDisk) ListPublicResources(fields []string, limit int, offset int, previewCrop bool, previewSize string, resourceType string) (*PublicResourcesList, error) {
+	return c.yad.ListPublicResources(fields, limit, offset, previewCrop, previewSize, resourceType)
+}
+
+// Get a list of published resources.
+func (c *CachedDisk) ListPublicResourcesWithOptions(opts *PublicResourcesListOptions) (*PublicResourcesList, error) {
+	return ListPublicResourcesWithOptions(c.yad, opts)
+}
+
+// Publish a resource.
+func (c *CachedDisk) PublishResource(path string, fields []string) (*Link, error) {
+	l, e := c.yad.PublishResource(path, fields)
+	c.mutated(nil, Path(path))
+	return l, e
+}
+
+// Unpublish a resource.
+func (c *CachedDisk) UnpublishResource(path string, fields []string) (*Link, error) {
+	l, e := c.yad.UnpublishResource(path, fields)
+	c.mutated(nil, Path(path))
+	return l, e
+}
+
+// Upload file to Disk by URL.
+func (c *CachedDisk) UploadExternalResource(path string, externalURL string, disableRedirects bool, fields []string) (*Link, error) {
+	return c.UploadExternalResourceWithOptions(path, externalURL, &UploadExternalOptions{DisableRedirects: disableRedirects, Fields: fields})
+}
+
+// Upload file to Disk by URL.
+func (c *CachedDisk) UploadExternalResourceWithOptions(path string, externalURL string, opts *UploadExternalOptions) (*Link, error) {
+	l, e := UploadExternalResourceWithOptions(c.yad, path, externalURL, opts)
+	c.mutated(l, Path(path))
+	return l, e
+}
+
+// Get file download link.
+//
+// The path is invalidated now and again when the upload through PerformUpload or PerformPartialUpload finishes
+// before the TTL passes.
+func (c *CachedDisk) GetResourceUploadLink(path string, fields []string, overwrite bool) (*ResourceUploadLink, error) {
+	ur, e := c.yad.GetResourceUploadLink(path, fields, overwrite)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(Path(path))
+	if e == nil {
+		c.addPending(c.uploads, ur.Href, Path(path))
+	}
+	return ur, e
+}
+
+// Get meta-information about a public file or directory.
+func (c *CachedDisk) GetPublicResource(publicKey string, fields []string, limit int, offset int, path string, previewCrop bool, previewSize string, sort string) (*PublicResource, error) {
+	return c.yad.GetPublicResource(publicKey, fields, limit, offset, path, previewCrop, previewSize, sort)
+}
+
+// Get meta-information about a public file or directory.
+func (c *CachedDisk) GetPublicResourceWithOptions(publicKey string, opts *PublicResourceOptions) (*PublicResource, error) {
+	return GetPublicResourceWithOptions(c.yad, publicKey, opts)
+}
+
+// Get a link to download a public resource.
+func (c *CachedDisk) GetPublicResourceDownloadLink(publicKey string, fields []string, path string) (*Link, error) {
+	return c.yad.GetPublicResourceDownloadLink(publicKey, fields, path)
+}
+
+// Save the public resource to the Downloads folder.
+//
+// Without savePath the whole cache is dropped, as the Downloads folder is not known.
+func (c *CachedDisk) SaveToDiskPublicResource(publicKey string, fields []string, forceAsync bool, name string, path string, savePath string) (*Link, error) {
+	return c.SaveToDiskPublicResourceWithOptions(publicKey, &SaveToDiskOptions{
+		Fields:     fields,
+		ForceAsync: forceAsync,
+		Name:       name,
+		Path:       path,
+		SavePath:   savePath,
+	})
+}
+
+// Save the public resource to the Downloads folder.
+//
+// Without SavePath the whole cache is dropped, as the Downloads folder is not known.
+func (c *CachedDisk) SaveToDiskPublicResourceWithOptions(publicKey string, opts *SaveToDiskOptions) (*Link, error) {
+	l, e := SaveToDiskPublicResourceWithOptions(c.yad, publicKey, opts)
+	if opts == nil || opts.SavePath == "" {
+		c.Purge()
+	} else {
+		c.mutated(l, Path(opts.SavePath))
+	}
+	return l, e
+}
+
+// Get the status of an asynchronous operation.
+func (c *CachedDisk) GetOperationStatus(operationID string, fields []string) (*OperationStatus, error) {
+	s, e := c.yad.GetOperationStatus(operationID, fields)
+	if e != nil {
+		return s, e
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := c.pending(c.operations, operationID)
+	switch {
+	case p == nil:
+	case s.Status == OperationStatusInProgress:
+		p.expires = c.now().Add(c.ttl)
+	default:
+		delete(c.operations, operationID)
+		c.invalidate(p.paths...)
+	}
+	return s, e
+}
+
+// This custom method to upload data by link.
+func (c *CachedDisk) PerformUpload(ur *ResourceUploadLink, data *bytes.Buffer) (*PerformUpload, error) {
+	pu, e := c.yad.PerformUpload(ur, data)
+	c.uploaded(ur)
+	return pu, e
+}
+
+// This custom method to upload data by link.
+func (c *CachedDisk) PerformPartialUpload(ur *ResourceUploadLink, data *bytes.Buffer, partSize int64) (*PerformUpload, error) {
+	pu, e := c.yad.PerformPartialUpload(ur, data, partSize)
+	c.uploaded(ur)
+	return pu, e
+}
+
+func (c *CachedDisk) uploaded(ur *ResourceUploadLink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p := c.pending(c.uploads, ur.Href); p != nil {
+		delete(c.uploads, ur.Href)
+		c.invalidate(p.paths...)
+	}
+}
@@ -0,0 +1,231 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// getCountingDisk counts the resource requests reaching the fake, which wait for release if it is set.
+type getCountingDisk struct {
+	*yadisktest.Disk
+	mu      sync.Mutex
+	gets    map[yadisk.Path]int
+	release chan struct{}
+}
+
+func (c *getCountingDisk) GetResourceWithOptions(path string, opts *yadisk.ResourceOptions) (*yadisk.Resource, error) {
+	c.mu.Lock()
+	c.gets[yadisk.Path(path).Clean()]++
+	c.mu.Unlock()
+	if c.release != nil {
+		<-c.release
+	}
+	return c.Disk.GetResourceWithOptions(path, opts)
+}
+
+func (c *getCountingDisk) count(p yadisk.Path) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gets[p.Clean()]
+}
+
+func newCachedDisk(t *testing.T, now func() time.Time) (*yadisk.CachedDisk, *getCountingDisk) {
+	d := &getCountingDisk{Disk: yadisktest.New(nil), gets: make(map[yadisk.Path]int)}
+	for _, p := range []string{"/a/f.txt", "/b/g.txt", "/other/h.txt"} {
+		if err := d.WriteFile(p, []byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return yadisk.NewCachedDisk(d, &yadisk.CacheOptions{TTL: time.Minute, Now: now}), d
+}
+
+func TestCachedDisk_GetResource(t *testing.T) {
+	now := time.Now()
+	c, d := newCachedDisk(t, func() time.Time { return now })
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetResource("/a", nil, 0, 0, false, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, _ := c.GetResourceWithOptions("disk:/a/", &yadisk.ResourceOptions{})
+	if d.count("/a") != 1 {
+		t.Errorf("requests of /a = %d, want 1", d.count("/a"))
+	}
+	r.Embedded.Items = nil
+	if r, _ := c.GetResourceWithOptions("/a", &yadisk.ResourceOptions{}); len(r.Embedded.Items) != 1 {
+		t.Errorf("GetResource() items = %d after the caller modified a result, want 1", len(r.Embedded.Items))
+	}
+
+	_, _ = c.GetResourceWithOptions("/a", &yadisk.ResourceOptions{Limit: yadisk.Int(1)})
+	if d.count("/a") != 2 {
+		t.Errorf("requests of /a with other options = %d, want 2", d.count("/a"))
+	}
+
+	now = now.Add(2 * time.Minute)
+	_, _ = c.GetResource("/a", nil, 0, 0, false, "", "")
+	if d.count("/a") != 3 {
+		t.Errorf("requests of /a after the TTL = %d, want 3", d.count("/a"))
+	}
+}
+
+func TestCachedDisk_invalidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *yadisk.CachedDisk, d *getCountingDisk) error
+		// Paths that must be requested again, the others must be served from the cache.
+		changed []yadisk.Path
+	}{
+		{"create", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			_, err := c.CreateResource("/a/new", nil)
+			return err
+		}, []yadisk.Path{"/", "/a"}},
+		{"delete", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			_, err := c.DeleteResource("/a/f.txt", nil, false, "", false)
+			return err
+		}, []yadisk.Path{"/", "/a", "/a/f.txt"}},
+		{"delete_folder", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			_, err := c.DeleteResource("/a", nil, false, "", false)
+			return err
+		}, []yadisk.Path{"/", "/a", "/a/f.txt"}},
+		{"move", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			_, err := c.MoveResource("/a/f.txt", "/b/f.txt", nil, false, false)
+			return err
+		}, []yadisk.Path{"/", "/a", "/a/f.txt", "/b"}},
+		{"copy", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			_, err := c.CopyResource("/a/f.txt", "/b/f.txt", nil, false, false)
+			return err
+		}, []yadisk.Path{"/", "/b"}},
+		{"update", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			patch, err := yadisk.NewResourcePatch(map[string]interface{}{"k": "v"})
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateResource("/a/f.txt", nil, patch)
+			return err
+		}, []yadisk.Path{"/", "/a", "/a/f.txt"}},
+		{"upload", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			ur, err := c.GetResourceUploadLink("/b/new.txt", nil, false)
+			if err != nil {
+				return err
+			}
+			// Cache the listing again while the upload has not finished.
+			_, _ = c.GetResource("/b", nil, 0, 0, false, "", "")
+			_, err = c.PerformUpload(ur, bytes.NewBufferString("new"))
+			return err
+		}, []yadisk.Path{"/", "/b"}},
+		{"restore", func(c *yadisk.CachedDisk, d *getCountingDisk) error {
+			if _, err := d.DeleteResource("/b/g.txt", nil, false, "", false); err != nil {
+				return err
+			}
+			trash, err := d.GetTrashResource("trash:/", nil, 0, 0, false, "", "")
+			if err != nil {
+				return err
+			}
+			_, err = c.RestoreFromTrash(trash.Embedded.Items[0].Path, nil, false, "", false)
+			return err
+		}, []yadisk.Path{"/", "/b"}},
+	}
+	paths := []yadisk.Path{"/", "/a", "/a/f.txt", "/b", "/other"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newCachedDisk(t, nil)
+			for _, p := range paths {
+				_, _ = c.GetResource(string(p), nil, 0, 0, false, "", "")
+			}
+			if err := tt.mutate(c, d); err != nil {
+				t.Fatal(err)
+			}
+			changed := make(map[yadisk.Path]bool)
+			for _, p := range tt.changed {
+				changed[p] = true
+			}
+			for _, p := range paths {
+				before := d.count(p)
+				_, _ = c.GetResource(string(p), nil, 0, 0, false, "", "")
+				if requested := d.count(p) > before; requested != changed[p] {
+					t.Errorf("%s requested again = %v, want %v", p, requested, changed[p])
+				}
+			}
+		})
+	}
+}
+
+func TestCachedDisk_coalescing(t *testing.T) {
+	c, d := newCachedDisk(t, nil)
+	d.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	get := func() {
+		defer wg.Done()
+		if r, err := c.GetResource("/a", nil, 0, 0, false, "", ""); err != nil || r.Path != "disk:/a" {
+			t.Errorf("GetResource() = %v, error = %v", r, err)
+		}
+	}
+	wg.Add(1)
+	go get()
+	for d.count("/a") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go get()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(d.release)
+	wg.Wait()
+	if d.count("/a") != 1 {
+		t.Errorf("requests of /a = %d, want 1", d.count("/a"))
+	}
+}
+
+func TestCachedDisk_operationExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		polled bool
+	}{
+		{"polled", true},
+		{"expired", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			now := func() time.Time { return clock }
+			d := &getCountingDisk{Disk: yadisktest.New(&yadisktest.Options{OperationDelay: 80 * time.Second, Now: now}), gets: make(map[yadisk.Path]int)}
+			if err := d.WriteFile("/a/f.txt", []byte("f")); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.WriteFile("/b/g.txt", []byte("g")); err != nil {
+				t.Fatal(err)
+			}
+			c := yadisk.NewCachedDisk(d, &yadisk.CacheOptions{TTL: time.Minute, Now: now})
+
+			l, err := c.CopyResource("/a/f.txt", "/b/f.txt", nil, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, _ := yadisk.OperationID(l)
+			clock = clock.Add(50 * time.Second)
+			if tt.polled {
+				_, _ = c.GetOperationStatus(id, nil)
+			}
+			clock = clock.Add(5 * time.Second)
+			_, _ = c.GetResource("/b", nil, 0, 0, false, "", "")
+			clock = clock.Add(35 * time.Second)
+			if s, err := c.GetOperationStatus(id, nil); err != nil || s.Status != yadisk.OperationStatusSuccess {
+				t.Fatalf("GetOperationStatus() = %v, error = %v", s, err)
+			}
+
+			before := d.count("/b")
+			_, _ = c.GetResource("/b", nil, 0, 0, false, "", "")
+			if requested := d.count("/b") > before; requested != tt.polled {
+				t.Errorf("/b requested again = %v, want %v", requested, tt.polled)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package yadisk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Options of CopyBetween.
+type CopyOptions struct {
+	// Number of files transferred at the same time, 4 by default.
+	Concurrency int
+	// Attempts to transfer a file after the first failure, 2 by default. Negative disables retries.
+	Retries int
+	// Delay before a retry, multiplied by the number of the attempt, 1 second by default.
+	RetryDelay time.Duration
+	// Replace destination files with a different md5.
+	Overwrite bool
+	// Clients of the download and the upload links, http.DefaultClient by default.
+	SrcHTTPClient *http.Client
+	DstHTTPClient *http.Client
+	// Checks the space of dst before each file, if set. Files that cannot fit fail with a *QuotaError.
+	Quota *QuotaGuard
+}
+
+// CopyReport is the result of CopyBetween and DownloadPublic.
+type CopyReport struct {
+	// Files transferred.
+	Copied int
+	// Files skipped because the destination has the same contents.
+	Skipped int
+	// Bytes transferred.
+	Bytes int64
+	// Files that failed to transfer.
+	Failed []CopyFailure
+}
+
+// CopyFailure is a file that failed to transfer.
+type CopyFailure struct {
+	Path Path
+	Err  error
+}
+
+type copyJob struct {
+	src *Resource
+	dst Path
+}
+
+// CopyBetween copies a file or a folder tree from one Disk to another, e.g. between two accounts.
+//
+// File contents are streamed from the download links of src to the upload links of dst, nothing is stored locally.
+// Files whose md5 matches the destination are skipped, so a failed copy resumes where it stopped when called again.
+// The error is non-nil if the tree could not be listed or some files failed, see CopyReport.Failed.
+func CopyBetween(ctx context.Context, src YaDisk, srcPath Path, dst YaDisk, dstPath Path, opts *CopyOptions) (*CopyReport, error) {
+	o := CopyOptions{Concurrency: 4, Retries: 2, RetryDelay: time.Second}
+	if opts != nil {
+		o = *opts
+		if o.Concurrency <= 0 {
+			o.Concurrency = 4
+		}
+		if o.Retries == 0 {
+			o.Retries = 2
+		}
+		if o.RetryDelay == 0 {
+			o.RetryDelay = time.Second
+		}
+	}
+	fields := []string{"name", "path", "type", "size", "md5"}
+	root, e := GetResourceWithOptions(src, string(srcPath), &ResourceOptions{Fields: fields, Limit: Int(1)})
+	if e != nil {
+		return nil, e
+	}
+
+	report := new(CopyReport)
+	var mu sync.Mutex
+	jobs := make(chan copyJob)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				copied, e := copyFileRetrying(ctx, src, dst, job, &o)
+				mu.Lock()
+				switch {
+				case e != nil:
+					report.Failed = append(report.Failed, CopyFailure{Path: Path(job.src.Path), Err: e})
+				case copied:
+					report.Copied++
+					report.Bytes += job.src.Size
+				default:
+					report.Skipped++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if root.Type != ResourceTypeDir {
+		e = sendJob(ctx, jobs, copyJob{src: root, dst: dstPath})
+	} else {
+		dirs := NewDirCache(dst)
+		if e = dirs.MkdirAll(ctx, dstPath); e == nil {
+			e = walk(ctx, src, Path(root.Path), embeddedFields(fields...), func(r *Resource) error {
+				rel, e := Path(root.Path).Rel(Path(r.Path))
+				if e != nil {
+					return e
+				}
+				target := dstPath.Join(rel)
+				if r.Type == ResourceTypeDir {
+					return dirs.MkdirAll(ctx, target)
+				}
+				return sendJob(ctx, jobs, copyJob{src: r, dst: target})
+			})
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if e != nil {
+		return report, e
+	}
+	if e = ctx.Err(); e != nil {
+		return report, e
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("yadisk: %d of %d files failed to copy, first: %v",
+			len(report.Failed), len(report.Failed)+report.Copied+report.Skipped, report.Failed[0].Err)
+	}
+	return report, nil
+}
+
+func sendJob(ctx context.Context, jobs chan<- copyJob, job copyJob) error {
+	select {
+	case jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func copyFileRetrying(ctx context.Context, src YaDisk, dst YaDisk, job copyJob, o *CopyOptions) (copied bool, e error) {
+	for attempt := 0; ; attempt++ {
+		copied, e = copyFile(ctx, src, dst, job, o)
+		if _, quota := e.(*QuotaError); e == nil || quota || attempt >= o.Retries || ctx.Err() != nil || IsErrorID(e, ErrorIDResourceAlreadyExists) {
+			return
+		}
+		select {
+		case <-time.After(o.RetryDelay * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// copyFile streams one file, it returns false if the destination already has the same contents.
+func copyFile(ctx context.Context, src YaDisk, dst YaDisk, job copyJob, o *CopyOptions) (bool, error) {
+	if e := ctx.Err(); e != nil {
+		return false, e
+	}
+	existing, e := GetResourceWithOptions(dst, string(job.dst), &ResourceOptions{Fields: []string{"type", "md5"}})
+	switch {
+	case e == nil && existing.Type == ResourceTypeFile && existing.Md5 != "" && existing.Md5 == job.src.Md5:
+		return false, nil
+	case e != nil && !IsErrorID(e, ErrorIDNotFound):
+		return false, e
+	}
+
+	if o.Quota != nil {
+		release, e := o.Quota.Reserve(job.dst, job.src.Size, o.Overwrite)
+		if e != nil {
+			return false, e
+		}
+		defer release()
+	}
+	link, e := src.GetResourceDownloadLink(job.src.Path, nil)
+	if e != nil {
+		return false, e
+	}
+	upload, e := dst.GetResourceUploadLink(string(job.dst), nil, o.Overwrite)
+	if e != nil {
+		return false, e
+	}
+	resp, e := openHref(ctx, httpClient(o.SrcHTTPClient), link.Href)
+	if e != nil {
+		return false, e
+	}
+	defer resp.Body.Close()
+	size := job.src.Size
+	if resp.ContentLength >= 0 {
+		size = resp.ContentLength
+	}
+	return true, streamUpload(ctx, httpClient(o.DstHTTPClient), upload, resp.Body, size)
+}
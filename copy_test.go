@@ -0,0 +1,104 @@
+package yadisk_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// failingTransport fails the first uploads and passes the rest to next.
+type failingTransport struct {
+	mu    sync.Mutex
+	fails int
+	next  http.RoundTripper
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	fail := req.Method == http.MethodPut && f.fails > 0
+	if fail {
+		f.fails--
+	}
+	f.mu.Unlock()
+	if fail {
+		return nil, errors.New("connection reset")
+	}
+	return f.next.RoundTrip(req)
+}
+
+func readFile(t *testing.T, d *yadisktest.Disk, p yadisk.Path) string {
+	l, err := d.GetResourceDownloadLink(string(p), nil)
+	if err != nil {
+		t.Fatalf("GetResourceDownloadLink(%q) error = %v", p, err)
+	}
+	resp, err := d.HTTPClient().Get(l.Href)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	return string(b)
+}
+
+func TestCopyBetween(t *testing.T) {
+	ctx := context.Background()
+	src := yadisktest.New(nil)
+	for _, p := range []string{"/team/a.txt", "/team/docs/b.txt", "/team/docs/deep/c.txt", "/other.txt"} {
+		_ = src.WriteFile(p, []byte("data of "+p))
+	}
+	dst := yadisktest.New(nil)
+	_ = dst.WriteFile("/migrated/a.txt", []byte("data of /team/a.txt"))
+
+	transport := &failingTransport{fails: 1, next: dst.HTTPClient().Transport}
+	opts := &yadisk.CopyOptions{
+		Concurrency:   2,
+		Retries:       -1,
+		SrcHTTPClient: src.HTTPClient(),
+		DstHTTPClient: &http.Client{Transport: transport},
+	}
+	report, err := yadisk.CopyBetween(ctx, src, "/team", dst, "/migrated", opts)
+	if err == nil || len(report.Failed) != 1 || report.Copied != 1 || report.Skipped != 1 {
+		t.Fatalf("CopyBetween() = %+v, error = %v, want one failure", report, err)
+	}
+
+	// The second run resumes with the failed file only.
+	report, err = yadisk.CopyBetween(ctx, src, "/team", dst, "/migrated", opts)
+	if err != nil || report.Copied != 1 || report.Skipped != 2 {
+		t.Fatalf("CopyBetween() = %+v, error = %v", report, err)
+	}
+	for _, p := range []string{"/team/a.txt", "/team/docs/b.txt", "/team/docs/deep/c.txt"} {
+		target := yadisk.Path("/migrated").Join(p[len("/team"):])
+		if got := readFile(t, dst, target); got != "data of "+p {
+			t.Errorf("%v = %q", target, got)
+		}
+	}
+	if _, err := dst.GetResource("/other.txt", nil, 0, 0, false, "", ""); !yadisk.IsErrorID(err, yadisk.ErrorIDNotFound) {
+		t.Errorf("file outside of the tree copied, error = %v", err)
+	}
+}
+
+func TestCopyBetween_file(t *testing.T) {
+	ctx := context.Background()
+	src := yadisktest.New(nil)
+	_ = src.WriteFile("/a.txt", []byte("new"))
+	dst := yadisktest.New(nil)
+	_ = dst.WriteFile("/b.txt", []byte("old"))
+	opts := &yadisk.CopyOptions{Retries: -1, SrcHTTPClient: src.HTTPClient(), DstHTTPClient: dst.HTTPClient()}
+
+	if _, err := yadisk.CopyBetween(ctx, src, "/a.txt", dst, "/b.txt", opts); err == nil {
+		t.Fatalf("CopyBetween() without overwrite error = %v", err)
+	}
+	opts.Overwrite = true
+	if report, err := yadisk.CopyBetween(ctx, src, "/a.txt", dst, "/b.txt", opts); err != nil || report.Copied != 1 {
+		t.Fatalf("CopyBetween() = %+v, error = %v", report, err)
+	}
+	if got := readFile(t, dst, "/b.txt"); got != "new" {
+		t.Errorf("/b.txt = %q, want new", got)
+	}
+}
@@ -0,0 +1,471 @@
+package yadisk
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CryptAlgorithm is the authenticated encryption of the segments, recorded in the header of encrypted files.
+type CryptAlgorithm byte
+
+// Encryption algorithms.
+const (
+	// AES-256-GCM with a key derived from the master key and the salt of the file.
+	CryptAES256GCM CryptAlgorithm = 1
+)
+
+// Version of the format of encrypted files written by Crypter.
+const CryptVersion = 1
+
+// Sizes of plaintext segments.
+const (
+	DefaultSegmentSize = 64 << 10
+	MaxSegmentSize     = 16 << 20
+)
+
+// ErrDecrypt is returned for data that was modified, truncated or encrypted with another key.
+var ErrDecrypt = errors.New("yadisk: message authentication failed")
+
+var cryptMagic = []byte("YDXC")
+
+const (
+	cryptKeySize  = 32
+	cryptSaltSize = 16
+	cryptOverhead = 16
+	// Magic, version, algorithm, segment size, salt and the length of the key ID.
+	cryptFixedHeader = 4 + 1 + 1 + 4 + cryptSaltSize + 1
+)
+
+// MaxEncryptedName is the maximum length of a name encrypted by Crypter.EncryptName, the limit of names on the Disk.
+const MaxEncryptedName = 255
+
+// Lowercase base32 without padding, encrypted names are safe for case-insensitive file systems.
+var nameEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+
+// KeyProvider supplies the master keys of the encryption, 32 bytes each.
+type KeyProvider interface {
+	// Key returns the key with the ID, or the current key and its ID if id is empty.
+	Key(id string) (key []byte, keyID string, e error)
+}
+
+// StaticKeys is a KeyProvider of keys held in memory. Old keys are kept to decrypt the files encrypted with them.
+type StaticKeys struct {
+	// ID of the key used to encrypt.
+	Current string
+	Keys    map[string][]byte
+}
+
+func (k *StaticKeys) Key(id string) ([]byte, string, error) {
+	if id == "" {
+		id = k.Current
+	}
+	key, ok := k.Keys[id]
+	if !ok {
+		return nil, "", fmt.Errorf("yadisk: unknown key %q", id)
+	}
+	return key, id, nil
+}
+
+// Crypter encrypts file contents in segments, so that any range of a file can be decrypted alone, and file names.
+//
+// An encrypted file is a header with the version, the algorithm, the segment size, a random salt and the key ID,
+// followed by the segments. Each segment is sealed with its index, a flag for the last one and the header,
+// so reordered, truncated or modified data fails to decrypt with ErrDecrypt.
+type Crypter struct {
+	keys        KeyProvider
+	segmentSize int
+}
+
+// NewCrypter returns a Crypter of the keys, segmentSize is DefaultSegmentSize if zero.
+func NewCrypter(keys KeyProvider, segmentSize int) (*Crypter, error) {
+	if segmentSize == 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if segmentSize < 0 || segmentSize > MaxSegmentSize {
+		return nil, fmt.Errorf("yadisk: segment size %d out of range", segmentSize)
+	}
+	return &Crypter{keys: keys, segmentSize: segmentSize}, nil
+}
+
+// Encrypt returns the encrypted stream of r and its size, -1 if size, the size of the plaintext, is negative.
+func (c *Crypter) Encrypt(r io.Reader, size int64) (io.Reader, int64, error) {
+	key, id, e := c.keys.Key("")
+	if e != nil {
+		return nil, 0, e
+	}
+	if len(id) > 255 {
+		return nil, 0, fmt.Errorf("yadisk: key ID %q is too long", id)
+	}
+	h := &cryptHeader{version: CryptVersion, algorithm: CryptAES256GCM, segmentSize: c.segmentSize, keyID: id}
+	h.salt = make([]byte, cryptSaltSize)
+	if _, e := io.ReadFull(rand.Reader, h.salt); e != nil {
+		return nil, 0, e
+	}
+	h.raw = h.marshal()
+	s, e := newSegmenter(h, key)
+	if e != nil {
+		return nil, 0, e
+	}
+	encrypted := int64(-1)
+	if size >= 0 {
+		seg := int64(h.segmentSize)
+		encrypted = int64(len(h.raw)) + size/seg*(seg+cryptOverhead) + size%seg + cryptOverhead
+	}
+	return &encryptReader{src: r, seg: s, plain: make([]byte, h.segmentSize), buf: h.raw}, encrypted, nil
+}
+
+// Decrypt returns the plaintext of the encrypted stream r.
+func (c *Crypter) Decrypt(r io.Reader) (io.Reader, error) {
+	h, e := readCryptHeader(r)
+	if e != nil {
+		return nil, e
+	}
+	s, e := c.segmenter(h)
+	if e != nil {
+		return nil, e
+	}
+	return &decryptReader{src: r, seg: s, ct: make([]byte, h.segmentSize+cryptOverhead)}, nil
+}
+
+// DecryptAt returns the plaintext of the encrypted file r of the given size, with random access.
+func (c *Crypter) DecryptAt(r io.ReaderAt, size int64) (*DecryptedFile, error) {
+	h, e := readCryptHeader(io.NewSectionReader(r, 0, size))
+	if e != nil {
+		return nil, e
+	}
+	s, e := c.segmenter(h)
+	if e != nil {
+		return nil, e
+	}
+	seg := int64(h.segmentSize)
+	body := size - int64(len(h.raw))
+	rem := body%(seg+cryptOverhead) - cryptOverhead
+	if body < 0 || rem < 0 {
+		return nil, ErrDecrypt
+	}
+	return &DecryptedFile{r: r, seg: s, offset: int64(len(h.raw)), size: body/(seg+cryptOverhead)*seg + rem}, nil
+}
+
+// EncryptName encrypts a file or folder name deterministically with the current key, the same name gives the same result.
+// The ID of the key is stored in the encrypted name, so names stay readable after the current key is rotated,
+// but the same name then encrypts differently. Names are not bound to their folder.
+// An encrypted name is 1.6 times as long as the name, its key ID and 18 bytes,
+// names that would exceed MaxEncryptedName are refused.
+func (c *Crypter) EncryptName(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("yadisk: empty name")
+	}
+	enc, mac, id, e := c.nameKeys("")
+	if e != nil {
+		return "", e
+	}
+	if len(id) > 255 {
+		return "", fmt.Errorf("yadisk: key ID %q is too long", id)
+	}
+	if n := nameEncoding.EncodedLen(1 + len(id) + aes.BlockSize + len(name)); n > MaxEncryptedName {
+		return "", fmt.Errorf("yadisk: encrypted name of %d bytes exceeds %d", n, MaxEncryptedName)
+	}
+	iv := hmacSum(mac, []byte(name))[:aes.BlockSize]
+	out := append(append([]byte{byte(len(id))}, id...), iv...)
+	ct := make([]byte, len(name))
+	cipher.NewCTR(enc, iv).XORKeyStream(ct, []byte(name))
+	out = append(out, ct...)
+	return nameEncoding.EncodeToString(out), nil
+}
+
+// DecryptName returns the name encrypted by EncryptName, with the key it was encrypted with.
+func (c *Crypter) DecryptName(encrypted string) (string, error) {
+	data, e := nameEncoding.DecodeString(encrypted)
+	if e != nil || len(data) == 0 || len(data) <= 1+int(data[0])+aes.BlockSize {
+		return "", ErrDecrypt
+	}
+	id, data := string(data[1:1+int(data[0])]), data[1+int(data[0]):]
+	enc, mac, _, e := c.nameKeys(id)
+	if e != nil {
+		return "", e
+	}
+	iv, name := data[:aes.BlockSize], make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCTR(enc, iv).XORKeyStream(name, data[aes.BlockSize:])
+	if !hmac.Equal(iv, hmacSum(mac, name)[:aes.BlockSize]) {
+		return "", ErrDecrypt
+	}
+	return string(name), nil
+}
+
+func (c *Crypter) nameKeys(id string) (cipher.Block, []byte, string, error) {
+	key, id, e := c.keys.Key(id)
+	if e != nil {
+		return nil, nil, "", e
+	}
+	if len(key) != cryptKeySize {
+		return nil, nil, "", fmt.Errorf("yadisk: key of %d bytes, want %d", len(key), cryptKeySize)
+	}
+	enc, e := aes.NewCipher(hmacSum(key, []byte("yadisk name encryption")))
+	if e != nil {
+		return nil, nil, "", e
+	}
+	return enc, hmacSum(key, []byte("yadisk name authentication")), id, nil
+}
+
+func (c *Crypter) segmenter(h *cryptHeader) (*segmenter, error) {
+	key, _, e := c.keys.Key(h.keyID)
+	if e != nil {
+		return nil, e
+	}
+	return newSegmenter(h, key)
+}
+
+// DecryptedFile is the plaintext of an encrypted file, decrypted on demand by segments.
+type DecryptedFile struct {
+	r   io.ReaderAt
+	seg *segmenter
+	// Size of the header.
+	offset int64
+	// Size of the plaintext.
+	size int64
+}
+
+// Size returns the size of the plaintext.
+func (f *DecryptedFile) Size() int64 {
+	return f.size
+}
+
+// ReadAt reads plaintext from off, decrypting the segments it spans.
+func (f *DecryptedFile) ReadAt(p []byte, off int64) (n int, e error) {
+	if off < 0 {
+		return 0, errors.New("yadisk: negative offset")
+	}
+	seg := int64(f.seg.size)
+	var ct []byte
+	for n < len(p) && off < f.size {
+		i := off / seg
+		last := i == f.size/seg
+		length := seg + cryptOverhead
+		if last {
+			length = f.size%seg + cryptOverhead
+		}
+		if ct == nil {
+			ct = make([]byte, seg+cryptOverhead)
+		}
+		m, e := f.r.ReadAt(ct[:length], f.offset+i*(seg+cryptOverhead))
+		if int64(m) < length {
+			if e == nil || e == io.EOF {
+				e = ErrDecrypt
+			}
+			return n, e
+		}
+		plain, e := f.seg.open(ct[:length], i, last)
+		if e != nil {
+			return n, e
+		}
+		c := copy(p[n:], plain[off-i*seg:])
+		n += c
+		off += int64(c)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type cryptHeader struct {
+	version     byte
+	algorithm   CryptAlgorithm
+	segmentSize int
+	salt        []byte
+	keyID       string
+	// Encoded header, authenticated with every segment.
+	raw []byte
+}
+
+func (h *cryptHeader) marshal() []byte {
+	b := make([]byte, 0, cryptFixedHeader+len(h.keyID))
+	b = append(b, cryptMagic...)
+	b = append(b, h.version, byte(h.algorithm))
+	b = append(b, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[len(b)-4:], uint32(h.segmentSize))
+	b = append(b, h.salt...)
+	b = append(b, byte(len(h.keyID)))
+	return append(b, h.keyID...)
+}
+
+func readCryptHeader(r io.Reader) (*cryptHeader, error) {
+	raw := make([]byte, cryptFixedHeader)
+	if _, e := io.ReadFull(r, raw); e != nil {
+		return nil, ErrDecrypt
+	}
+	if !bytes.Equal(raw[:4], cryptMagic) {
+		return nil, errors.New("yadisk: not an encrypted file")
+	}
+	h := &cryptHeader{
+		version:     raw[4],
+		algorithm:   CryptAlgorithm(raw[5]),
+		segmentSize: int(binary.BigEndian.Uint32(raw[6:10])),
+		salt:        raw[10 : 10+cryptSaltSize],
+	}
+	switch {
+	case h.version != CryptVersion:
+		return nil, fmt.Errorf("yadisk: unsupported encryption version %d", h.version)
+	case h.algorithm != CryptAES256GCM:
+		return nil, fmt.Errorf("yadisk: unsupported encryption algorithm %d", h.algorithm)
+	case h.segmentSize <= 0 || h.segmentSize > MaxSegmentSize:
+		return nil, fmt.Errorf("yadisk: segment size %d out of range", h.segmentSize)
+	}
+	id := make([]byte, raw[cryptFixedHeader-1])
+	if _, e := io.ReadFull(r, id); e != nil {
+		return nil, ErrDecrypt
+	}
+	h.keyID = string(id)
+	h.raw = append(raw, id...)
+	return h, nil
+}
+
+// segmenter seals and opens the segments of one file.
+type segmenter struct {
+	aead   cipher.AEAD
+	header []byte
+	size   int
+}
+
+func newSegmenter(h *cryptHeader, key []byte) (*segmenter, error) {
+	if len(key) != cryptKeySize {
+		return nil, fmt.Errorf("yadisk: key of %d bytes, want %d", len(key), cryptKeySize)
+	}
+	block, e := aes.NewCipher(hmacSum(key, append([]byte("yadisk file key"), h.salt...)))
+	if e != nil {
+		return nil, e
+	}
+	aead, e := cipher.NewGCM(block)
+	if e != nil {
+		return nil, e
+	}
+	return &segmenter{aead: aead, header: h.raw, size: h.segmentSize}, nil
+}
+
+// nonce is the index of the segment followed by the flag of the last segment.
+func (s *segmenter) nonce(i int64, last bool) []byte {
+	nonce := make([]byte, s.aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, uint64(i))
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+func (s *segmenter) seal(dst []byte, plain []byte, i int64, last bool) []byte {
+	return s.aead.Seal(dst, s.nonce(i, last), plain, s.header)
+}
+
+// open decrypts the segment in place.
+func (s *segmenter) open(ct []byte, i int64, last bool) ([]byte, error) {
+	plain, e := s.aead.Open(ct[:0], s.nonce(i, last), ct, s.header)
+	if e != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+type encryptReader struct {
+	src   io.Reader
+	seg   *segmenter
+	plain []byte
+	out   []byte
+	// Pending output, the header first.
+	buf   []byte
+	index int64
+	done  bool
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		// A short segment is the last one, a plaintext of whole segments ends with an empty one.
+		n, e := io.ReadFull(r.src, r.plain)
+		switch e {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			r.done = true
+		default:
+			return 0, e
+		}
+		r.out = r.seg.seal(r.out[:0], r.plain[:n], r.index, r.done)
+		r.buf = r.out
+		r.index++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type decryptReader struct {
+	src   io.Reader
+	seg   *segmenter
+	ct    []byte
+	buf   []byte
+	index int64
+	done  bool
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, e := io.ReadFull(r.src, r.ct)
+		switch e {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			r.done = true
+		default:
+			return 0, e
+		}
+		if r.done && n < cryptOverhead {
+			return 0, ErrDecrypt
+		}
+		plain, e := r.seg.open(r.ct[:n], r.index, r.done)
+		if e != nil {
+			return 0, e
+		}
+		r.buf = plain
+		r.index++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func hmacSum(key []byte, data []byte) []byte {
+	m := hmac.New(sha256.New, key)
+	_, _ = m.Write(data)
+	return m.Sum(nil)
+}
+
+// cryptPath applies fn to every name of p.
+func cryptPath(p Path, fn func(string) (string, error)) (Path, error) {
+	c, e := ParsePath(string(p))
+	if e != nil {
+		return "", e
+	}
+	if c.IsRoot() {
+		return c, nil
+	}
+	names := strings.Split(strings.TrimPrefix(c.Inner(), "/"), "/")
+	for i, name := range names {
+		if names[i], e = fn(name); e != nil {
+			return "", e
+		}
+	}
+	return c.Root().Path("/" + strings.Join(names, "/")), nil
+}
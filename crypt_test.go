@@ -0,0 +1,193 @@
+package yadisk
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func testKeys() *StaticKeys {
+	return &StaticKeys{Current: "k2", Keys: map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}}
+}
+
+func encrypt(t *testing.T, c *Crypter, plain []byte) []byte {
+	r, size, err := c.Encrypt(bytes.NewReader(plain), int64(len(plain)))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	ct, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if int64(len(ct)) != size {
+		t.Fatalf("Encrypt() size = %d, wrote %d", size, len(ct))
+	}
+	return ct
+}
+
+func TestCrypter_roundTrip(t *testing.T) {
+	c, err := NewCrypter(testKeys(), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, 1, 15, 16, 17, 32, 100} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+		ct := encrypt(t, c, plain)
+
+		r, err := c.Decrypt(bytes.NewReader(ct))
+		if err != nil {
+			t.Fatalf("Decrypt(%d) error = %v", size, err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil || !bytes.Equal(got, plain) {
+			t.Errorf("Decrypt(%d) = %x, error = %v, want %x", size, got, err, plain)
+		}
+
+		f, err := c.DecryptAt(bytes.NewReader(ct), int64(len(ct)))
+		if err != nil || f.Size() != int64(size) {
+			t.Fatalf("DecryptAt(%d) size = %v, error = %v", size, f, err)
+		}
+		for off := 0; off < size; off += 7 {
+			buf := make([]byte, 20)
+			n, err := f.ReadAt(buf, int64(off))
+			want := plain[off:]
+			if len(want) > len(buf) {
+				want = want[:len(buf)]
+			}
+			if !bytes.Equal(buf[:n], want) || (n < len(buf) && err != io.EOF) {
+				t.Errorf("ReadAt(%d, %d) = %x, error = %v, want %x", size, off, buf[:n], err, want)
+			}
+		}
+	}
+}
+
+func TestCrypter_tampered(t *testing.T) {
+	c, _ := NewCrypter(testKeys(), 16)
+	plain := bytes.Repeat([]byte("x"), 40)
+	ct := encrypt(t, c, plain)
+	header := cryptFixedHeader + 2
+	segment := 16 + cryptOverhead
+
+	flipped := append([]byte(nil), ct...)
+	flipped[header+segment+3] ^= 1
+	swapped := append(append(append([]byte(nil), ct[:header]...), ct[header+segment:header+2*segment]...), ct[header:header+segment]...)
+	swapped = append(swapped, ct[header+2*segment:]...)
+	badVersion := append([]byte(nil), ct...)
+	badVersion[4] = 9
+
+	tests := []struct {
+		name string
+		ct   []byte
+	}{
+		{"flipped", flipped},
+		{"swapped", swapped},
+		{"truncated_segment", ct[:len(ct)-1]},
+		{"dropped_last_segment", ct[:header+2*segment]},
+		{"bad_version", badVersion},
+		{"not_encrypted", plain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := c.Decrypt(bytes.NewReader(tt.ct))
+			if err == nil {
+				_, err = ioutil.ReadAll(r)
+			}
+			if err == nil {
+				t.Errorf("Decrypt() error = %v, wantErr true", err)
+			}
+			f, err := c.DecryptAt(bytes.NewReader(tt.ct), int64(len(tt.ct)))
+			if err == nil {
+				_, err = f.ReadAt(make([]byte, f.Size()), 0)
+			}
+			if err == nil {
+				t.Errorf("DecryptAt() error = %v, wantErr true", err)
+			}
+		})
+	}
+}
+
+func TestCrypter_rotatedKey(t *testing.T) {
+	keys := testKeys()
+	keys.Current = "k1"
+	c, _ := NewCrypter(keys, 0)
+	ct := encrypt(t, c, []byte("secret"))
+
+	keys.Current = "k2"
+	r, err := c.Decrypt(bytes.NewReader(ct))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if got, _ := ioutil.ReadAll(r); string(got) != "secret" {
+		t.Errorf("Decrypt() = %q, want %q", got, "secret")
+	}
+
+	delete(keys.Keys, "k1")
+	if _, err := c.Decrypt(bytes.NewReader(ct)); err == nil {
+		t.Errorf("Decrypt() with a missing key error = %v, wantErr true", err)
+	}
+}
+
+func TestCrypter_EncryptName(t *testing.T) {
+	c, _ := NewCrypter(testKeys(), 0)
+	a1, _ := c.EncryptName("report.pdf")
+	a2, _ := c.EncryptName("report.pdf")
+	b, _ := c.EncryptName("Report.pdf")
+	if a1 != a2 || a1 == b || bytes.ContainsAny([]byte(a1), "/ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		t.Errorf("EncryptName() = %q, %q, %q", a1, a2, b)
+	}
+	if got, err := c.DecryptName(a1); err != nil || got != "report.pdf" {
+		t.Errorf("DecryptName() = %q, error = %v", got, err)
+	}
+	if _, err := c.DecryptName(a1[:len(a1)-1] + "0"); err == nil {
+		t.Errorf("DecryptName() of a modified name error = %v, wantErr true", err)
+	}
+	if _, err := c.EncryptName(""); err == nil {
+		t.Errorf("EncryptName() of an empty name error = %v, wantErr true", err)
+	}
+}
+
+func TestCrypter_EncryptName_rotation(t *testing.T) {
+	keys := testKeys()
+	c, _ := NewCrypter(keys, 0)
+	keys.Current = "k1"
+	old, err := c.EncryptName("report.pdf")
+	if err != nil {
+		t.Fatalf("EncryptName() error = %v", err)
+	}
+
+	keys.Current = "k2"
+	if got, err := c.DecryptName(old); err != nil || got != "report.pdf" {
+		t.Errorf("DecryptName() after rotation = %q, error = %v", got, err)
+	}
+	if cur, _ := c.EncryptName("report.pdf"); cur == old {
+		t.Errorf("EncryptName() after rotation = %q, want a name under the current key", cur)
+	}
+
+	delete(keys.Keys, "k1")
+	if _, err := c.DecryptName(old); err == nil || err == ErrDecrypt {
+		t.Errorf("DecryptName() with a missing key error = %v, want unknown key", err)
+	}
+}
+
+func TestCrypter_EncryptName_length(t *testing.T) {
+	c, _ := NewCrypter(testKeys(), 0)
+	// 1.6 * (1 + 2 + 16 + 140) = 254.4 bytes, the longest name under the key "k2".
+	longest := strings.Repeat("a", 140)
+	enc, err := c.EncryptName(longest)
+	if err != nil || len(enc) > MaxEncryptedName {
+		t.Errorf("EncryptName() = %d bytes, error = %v", len(enc), err)
+	}
+	if got, err := c.DecryptName(enc); err != nil || got != longest {
+		t.Errorf("DecryptName() = %q, error = %v", got, err)
+	}
+	if _, err := c.EncryptName(longest + "a"); err == nil {
+		t.Errorf("EncryptName() of a too long name error = %v, wantErr true", err)
+	}
+}
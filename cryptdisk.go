@@ -0,0 +1,162 @@
+package yadisk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Options of NewEncryptedDisk.
+type EncryptionOptions struct {
+	// Size of the plaintext segments, DefaultSegmentSize by default.
+	SegmentSize int
+	// Encrypt the names of files and folders, see Crypter.EncryptName.
+	EncryptNames bool
+	// Client of the upload and download links, http.DefaultClient by default.
+	HTTPClient *http.Client
+	// Checks the space of the Disk before each upload of a known size, if set. Files that cannot fit fail with a *QuotaError.
+	Quota *QuotaGuard
+}
+
+// EncryptedDisk uploads and downloads files of a Disk encrypted on the client, so their contents are not readable by the storage.
+//
+// Paths are the plaintext paths, see Path for the paths of the resources on the Disk.
+type EncryptedDisk struct {
+	yad     YaDisk
+	crypter *Crypter
+	names   bool
+	client  *http.Client
+	quota   *QuotaGuard
+}
+
+// NewEncryptedDisk returns an encrypting wrapper of the uploads and downloads of yad with the keys.
+func NewEncryptedDisk(yad YaDisk, keys KeyProvider, opts *EncryptionOptions) (*EncryptedDisk, error) {
+	o := EncryptionOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	c, e := NewCrypter(keys, o.SegmentSize)
+	if e != nil {
+		return nil, e
+	}
+	return &EncryptedDisk{yad: yad, crypter: c, names: o.EncryptNames, client: httpClient(o.HTTPClient), quota: o.Quota}, nil
+}
+
+// Crypter returns the Crypter of the files and names.
+func (d *EncryptedDisk) Crypter() *Crypter {
+	return d.crypter
+}
+
+// Path returns the path of the resource on the Disk, with encrypted names if EncryptNames is set.
+func (d *EncryptedDisk) Path(p Path) (Path, error) {
+	if !d.names {
+		return ParsePath(string(p))
+	}
+	return cryptPath(p, d.crypter.EncryptName)
+}
+
+// DecryptPath returns the plaintext path of a resource on the Disk, e.g. of an item of a listing.
+func (d *EncryptedDisk) DecryptPath(p Path) (Path, error) {
+	if !d.names {
+		return ParsePath(string(p))
+	}
+	return cryptPath(p, d.crypter.DecryptName)
+}
+
+// Upload encrypts size bytes of r into the file at p, creating the missing folders.
+// A negative size uploads with chunked encoding.
+func (d *EncryptedDisk) Upload(ctx context.Context, p Path, r io.Reader, size int64, overwrite bool) error {
+	enc, e := d.Path(p)
+	if e != nil {
+		return e
+	}
+	if enc.IsRoot() {
+		return fmt.Errorf("yadisk: %q is not a file path", p)
+	}
+	body, n, e := d.crypter.Encrypt(r, size)
+	if e != nil {
+		return e
+	}
+	if d.quota != nil && n >= 0 {
+		release, e := d.quota.Reserve(enc, n, overwrite)
+		if e != nil {
+			return e
+		}
+		defer release()
+	}
+	if e := MkdirAll(ctx, d.yad, enc.Dir()); e != nil {
+		return e
+	}
+	link, e := d.yad.GetResourceUploadLink(string(enc), nil, overwrite)
+	if e != nil {
+		return e
+	}
+	return streamUpload(ctx, d.client, link, body, n)
+}
+
+// Download decrypts the file at p into w and returns the number of bytes written.
+func (d *EncryptedDisk) Download(ctx context.Context, p Path, w io.Writer) (int64, error) {
+	enc, e := d.Path(p)
+	if e != nil {
+		return 0, e
+	}
+	link, e := d.yad.GetResourceDownloadLink(string(enc), nil)
+	if e != nil {
+		return 0, e
+	}
+	resp, e := openHref(ctx, d.client, link.Href)
+	if e != nil {
+		return 0, e
+	}
+	defer resp.Body.Close()
+	r, e := d.crypter.Decrypt(resp.Body)
+	if e != nil {
+		return 0, e
+	}
+	return io.Copy(w, r)
+}
+
+// Open returns the file at p for random access, reads fetch the ranges of the segments they span.
+// Use io.NewSectionReader for sequential reads.
+func (d *EncryptedDisk) Open(ctx context.Context, p Path) (*DecryptedFile, error) {
+	enc, e := d.Path(p)
+	if e != nil {
+		return nil, e
+	}
+	r, e := GetResourceWithOptions(d.yad, string(enc), &ResourceOptions{Fields: []string{"type", "size"}})
+	if e != nil {
+		return nil, e
+	}
+	if r.Type != ResourceTypeFile {
+		return nil, fmt.Errorf("yadisk: %q is not a file", p)
+	}
+	link, e := d.yad.GetResourceDownloadLink(string(enc), nil)
+	if e != nil {
+		return nil, e
+	}
+	return d.crypter.DecryptAt(&hrefReaderAt{ctx: ctx, client: d.client, href: link.Href}, r.Size)
+}
+
+// hrefReaderAt reads a download link with range requests.
+type hrefReaderAt struct {
+	ctx    context.Context
+	client *http.Client
+	href   string
+}
+
+func (h *hrefReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	resp, e := openRange(h.ctx, h.client, h.href, off, int64(len(p)))
+	if e != nil {
+		return 0, e
+	}
+	defer resp.Body.Close()
+	n, e := io.ReadFull(resp.Body, p)
+	if e == io.ErrUnexpectedEOF {
+		e = io.EOF
+	}
+	return n, e
+}
@@ -0,0 +1,66 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func TestEncryptedDisk(t *testing.T) {
+	keys := &yadisk.StaticKeys{Current: "k", Keys: map[string][]byte{"k": bytes.Repeat([]byte{7}, 32)}}
+	plain := []byte(strings.Repeat("confidential ", 1000))
+	tests := []struct {
+		name  string
+		names bool
+	}{
+		{"contents", false},
+		{"names", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := yadisktest.New(nil)
+			enc, err := yadisk.NewEncryptedDisk(d, keys, &yadisk.EncryptionOptions{SegmentSize: 1024, EncryptNames: tt.names, HTTPClient: d.HTTPClient()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := enc.Upload(ctx, "/secret/notes.txt", bytes.NewReader(plain), int64(len(plain)), false); err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			p, _ := enc.Path("/secret/notes.txt")
+			if strings.Contains(string(p), "secret") != !tt.names {
+				t.Errorf("Path() = %q, EncryptNames %v", p, tt.names)
+			}
+			if back, err := enc.DecryptPath(p); err != nil || back != "disk:/secret/notes.txt" {
+				t.Errorf("DecryptPath() = %q, error = %v", back, err)
+			}
+			if stored := readFile(t, d, p); strings.Contains(stored, "confidential") {
+				t.Errorf("stored file contains the plaintext")
+			}
+
+			buf := new(bytes.Buffer)
+			if n, err := enc.Download(ctx, "/secret/notes.txt", buf); err != nil || n != int64(len(plain)) || !bytes.Equal(buf.Bytes(), plain) {
+				t.Errorf("Download() = %d, error = %v", n, err)
+			}
+
+			f, err := enc.Open(ctx, "/secret/notes.txt")
+			if err != nil || f.Size() != int64(len(plain)) {
+				t.Fatalf("Open() = %v, error = %v", f, err)
+			}
+			part := make([]byte, 100)
+			if _, err := f.ReadAt(part, 5000); err != nil || !bytes.Equal(part, plain[5000:5100]) {
+				t.Errorf("ReadAt() = %q, error = %v", part, err)
+			}
+			tail := make([]byte, 100)
+			if n, err := f.ReadAt(tail, int64(len(plain))-10); n != 10 || err != io.EOF {
+				t.Errorf("ReadAt() at the end = %d, error = %v", n, err)
+			}
+		})
+	}
+}
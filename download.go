@@ -0,0 +1,236 @@
+package yadisk
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Options of DownloadPublic.
+type DownloadOptions struct {
+	// Number of files downloaded at the same time, 4 by default.
+	Concurrency int
+	// Client of the download links, http.DefaultClient by default.
+	HTTPClient *http.Client
+}
+
+type downloadJob struct {
+	// Path relative to the downloaded resource, "/" for a file.
+	rel  string
+	item *PublicResource
+	file string
+}
+
+// DownloadPublic downloads a public file or folder tree into localDir, keeping the folder structure.
+//
+// publicKeyOrURL is the public key or any link accepted by ParsePublicURL, a path in the link selects a resource
+// inside the public folder. Downloaded files are verified against their md5
+// and sha256, files that are already up to date are skipped, so an interrupted download resumes when called again.
+func DownloadPublic(ctx context.Context, yad YaDisk, publicKeyOrURL string, localDir string, opts *DownloadOptions) (*CopyReport, error) {
+	o := DownloadOptions{Concurrency: 4}
+	if opts != nil {
+		o = *opts
+		if o.Concurrency <= 0 {
+			o.Concurrency = 4
+		}
+	}
+	client := httpClient(o.HTTPClient)
+	l, e := ParsePublicURL(publicKeyOrURL)
+	if e != nil {
+		return nil, e
+	}
+
+	report := new(CopyReport)
+	var mu sync.Mutex
+	jobs := make(chan downloadJob)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				downloaded, e := downloadPublicFile(ctx, yad, client, l, job)
+				mu.Lock()
+				switch {
+				case e != nil:
+					report.Failed = append(report.Failed, CopyFailure{Path: Path(job.rel), Err: e})
+				case downloaded:
+					report.Copied++
+					report.Bytes += job.item.Size
+				default:
+					report.Skipped++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	e = walkPublic(ctx, yad, l, func(rel string, item *PublicResource) error {
+		local := localPath(localDir, rel)
+		if item.Type == ResourceTypeDir {
+			return os.MkdirAll(local, 0755)
+		}
+		if rel == "/" {
+			// A public file is saved under its name.
+			if e := checkName(item.Name); e != nil {
+				return e
+			}
+			local = filepath.Join(localDir, item.Name)
+			if e := os.MkdirAll(localDir, 0755); e != nil {
+				return e
+			}
+		}
+		select {
+		case jobs <- downloadJob{rel: rel, item: item, file: local}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	if e != nil {
+		return report, e
+	}
+	if e = ctx.Err(); e != nil {
+		return report, e
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("yadisk: %d of %d files failed to download, first: %v",
+			len(report.Failed), len(report.Failed)+report.Copied+report.Skipped, report.Failed[0].Err)
+	}
+	return report, nil
+}
+
+// walkPublic calls fn for the resource of the link and everything below it, with paths relative to the resource.
+func walkPublic(ctx context.Context, yad YaDisk, l *PublicLink, fn func(rel string, r *PublicResource) error) error {
+	queue := []string{"/"}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		for offset := 0; ; offset += walkLimit {
+			if e := ctx.Err(); e != nil {
+				return e
+			}
+			r, e := GetPublicResourceWithOptions(yad, l.Key, &PublicResourceOptions{Path: path.Join("/", l.Path, dir), Limit: Int(walkLimit), Offset: Int(offset)})
+			if e != nil {
+				return e
+			}
+			if offset == 0 && dir == "/" {
+				if e := fn("/", r); e != nil {
+					return e
+				}
+				if r.Type != ResourceTypeDir {
+					return nil
+				}
+			}
+			for i := range r.Embedded.Items {
+				item := &r.Embedded.Items[i]
+				if e := checkName(item.Name); e != nil {
+					return e
+				}
+				rel := path.Join(dir, item.Name)
+				if e := fn(rel, item); e != nil {
+					return e
+				}
+				if item.Type == ResourceTypeDir {
+					queue = append(queue, rel)
+				}
+			}
+			if len(r.Embedded.Items) < walkLimit || offset+walkLimit >= r.Embedded.Total {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// checkName rejects names that would place a file outside of the local folder.
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("yadisk: unsafe name %q in the public folder", name)
+	}
+	return nil
+}
+
+// localPath returns the local path of rel inside dir.
+func localPath(dir string, rel string) string {
+	return filepath.Join(dir, filepath.FromSlash(path.Clean("/"+rel)))
+}
+
+// downloadPublicFile downloads a file unless the local copy has the same hashes, it returns false if it was skipped.
+func downloadPublicFile(ctx context.Context, yad YaDisk, client *http.Client, l *PublicLink, job downloadJob) (bool, error) {
+	if upToDate(job.file, job.item) {
+		return false, nil
+	}
+	if e := ctx.Err(); e != nil {
+		return false, e
+	}
+	link, e := yad.GetPublicResourceDownloadLink(l.Key, []string{"href"}, path.Join("/", l.Path, job.rel))
+	if e != nil {
+		return false, e
+	}
+	resp, e := openHref(ctx, client, link.Href)
+	if e != nil {
+		return false, e
+	}
+	defer resp.Body.Close()
+
+	tmp, e := ioutil.TempFile(filepath.Dir(job.file), "."+filepath.Base(job.file)+".*")
+	if e != nil {
+		return false, e
+	}
+	defer os.Remove(tmp.Name())
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	_, e = io.Copy(io.MultiWriter(tmp, md5Hash, sha256Hash), resp.Body)
+	if ce := tmp.Close(); e == nil {
+		e = ce
+	}
+	if e != nil {
+		return false, e
+	}
+	if e := verify(job.item, md5Hash, sha256Hash); e != nil {
+		return false, fmt.Errorf("yadisk: %s: %v", job.rel, e)
+	}
+	return true, os.Rename(tmp.Name(), job.file)
+}
+
+// upToDate reports whether the local file has the size and the hashes of the resource.
+func upToDate(file string, r *PublicResource) bool {
+	info, e := os.Stat(file)
+	if e != nil || !info.Mode().IsRegular() || info.Size() != r.Size || (r.Md5 == "" && r.Sha256 == "") {
+		return false
+	}
+	f, e := os.Open(file)
+	if e != nil {
+		return false
+	}
+	defer f.Close()
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	if _, e := io.Copy(io.MultiWriter(md5Hash, sha256Hash), f); e != nil {
+		return false
+	}
+	return verify(r, md5Hash, sha256Hash) == nil
+}
+
+// verify compares the hashes of the data with the hashes of the resource known to the API.
+func verify(r *PublicResource, md5Hash hash.Hash, sha256Hash hash.Hash) error {
+	if sum := hex.EncodeToString(md5Hash.Sum(nil)); r.Md5 != "" && sum != r.Md5 {
+		return fmt.Errorf("md5 %s, want %s", sum, r.Md5)
+	}
+	if sum := hex.EncodeToString(sha256Hash.Sum(nil)); r.Sha256 != "" && sum != r.Sha256 {
+		return fmt.Errorf("sha256 %s, want %s", sum, r.Sha256)
+	}
+	return nil
+}
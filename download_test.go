@@ -0,0 +1,119 @@
+package yadisk_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func publish(t *testing.T, d *yadisktest.Disk, p yadisk.Path) *yadisk.Resource {
+	if _, err := d.PublishResource(string(p), nil); err != nil {
+		t.Fatalf("PublishResource(%q) error = %v", p, err)
+	}
+	r, err := d.GetResource(string(p), nil, 0, 0, false, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestDownloadPublic(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/shared/a.txt", []byte("a"))
+	_ = d.WriteFile("/shared/sub/b.txt", []byte("bb"))
+	for i := 0; i < 105; i++ {
+		_ = d.WriteFile(fmt.Sprintf("/shared/many/%03d", i), []byte{byte(i)})
+	}
+	_ = d.WriteFile("/private.txt", []byte("private"))
+	r := publish(t, d, "/shared")
+
+	dir, err := ioutil.TempDir("", "yadisk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := &yadisk.DownloadOptions{Concurrency: 3, HTTPClient: d.HTTPClient()}
+
+	tests := []struct {
+		name    string
+		key     string
+		prepare func()
+		copied  int
+		skipped int
+	}{
+		{"by_url", r.PublicURL, func() {}, 107, 0},
+		{"up_to_date", r.PublicKey, func() {}, 0, 107},
+		{"changed_locally", r.PublicKey, func() {
+			_ = ioutil.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("xx"), 0644)
+		}, 1, 106},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			report, err := yadisk.DownloadPublic(ctx, d, tt.key, dir, opts)
+			if err != nil {
+				t.Fatalf("DownloadPublic() error = %v", err)
+			}
+			if report.Copied != tt.copied || report.Skipped != tt.skipped {
+				t.Errorf("DownloadPublic() copied = %d, skipped = %d, want %d, %d", report.Copied, report.Skipped, tt.copied, tt.skipped)
+			}
+		})
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "sub", "b.txt")); string(b) != "bb" {
+		t.Errorf("sub/b.txt = %q, want bb", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "private.txt")); !os.IsNotExist(err) {
+		t.Errorf("private.txt downloaded, error = %v", err)
+	}
+}
+
+func TestDownloadPublic_file(t *testing.T) {
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/doc.txt", []byte("doc"))
+	r := publish(t, d, "/doc.txt")
+	dir, err := ioutil.TempDir("", "yadisk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The web link of the file is accepted as well.
+	web := strings.Replace(r.PublicURL, "https://yadi.sk", "https://disk.yandex.ru", 1)
+	if _, err := yadisk.DownloadPublic(context.Background(), d, web, dir, &yadisk.DownloadOptions{HTTPClient: d.HTTPClient()}); err != nil {
+		t.Fatalf("DownloadPublic() error = %v", err)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "doc.txt")); string(b) != "doc" {
+		t.Errorf("doc.txt = %q, want doc", b)
+	}
+}
+
+func TestDownloadPublic_subfolder(t *testing.T) {
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/shared/a.txt", []byte("a"))
+	_ = d.WriteFile("/shared/sub/b.txt", []byte("bb"))
+	r := publish(t, d, "/shared")
+	dir, err := ioutil.TempDir("", "yadisk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	report, err := yadisk.DownloadPublic(context.Background(), d, r.PublicURL+"/sub", dir, &yadisk.DownloadOptions{HTTPClient: d.HTTPClient()})
+	if err != nil {
+		t.Fatalf("DownloadPublic() error = %v", err)
+	}
+	if report.Copied != 1 {
+		t.Errorf("DownloadPublic() copied = %d, want 1", report.Copied)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "b.txt")); string(b) != "bb" {
+		t.Errorf("b.txt = %q, want bb", b)
+	}
+}
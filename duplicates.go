@@ -0,0 +1,267 @@
+package yadisk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DuplicateKey is the property of the files compared by FindDuplicates.
+type DuplicateKey int
+
+const (
+	// Files with the same MD5 hash and size.
+	DuplicateMd5 DuplicateKey = iota
+	// Files with the same SHA-256 hash and size.
+	DuplicateSha256
+	// Files with the same size, only candidates for a closer comparison.
+	DuplicateSize
+)
+
+// KeepPolicy picks the file of a group of duplicates kept by RemoveDuplicates.
+type KeepPolicy int
+
+const (
+	// Keep the file created first.
+	KeepOldest KeepPolicy = iota
+	// Keep the file with the shortest path.
+	KeepShortestPath
+	// Keep the file in the first of the preferred folders, the oldest one if several files are in it or none is.
+	KeepPreferred
+)
+
+// Options of FindDuplicates.
+type DuplicateOptions struct {
+	// Property of the files compared, DuplicateMd5 by default.
+	By DuplicateKey
+	// One or more media types, see MediaTypes. All the files are compared if empty.
+	MediaType MediaType
+	// Files smaller than this are left out, empty files always are.
+	MinSize int64
+}
+
+// DuplicateGroup is a set of files with the same contents.
+type DuplicateGroup struct {
+	By DuplicateKey
+	// Hash of the files, or their size in decimal for DuplicateSize.
+	Key string
+	// Size of each file.
+	Size int64
+	// Files sorted by path.
+	Files []Resource
+}
+
+// Wasted returns the bytes taken by all the copies but one.
+func (g *DuplicateGroup) Wasted() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// FindDuplicates pages through the flat list of files and groups the files with the same contents.
+// The groups are sorted by the wasted bytes, largest first.
+func FindDuplicates(ctx context.Context, yad YaDisk, opts *DuplicateOptions) ([]DuplicateGroup, error) {
+	o := DuplicateOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.MediaType.Validate(); e != nil {
+		return nil, e
+	}
+	fields := []string{"limit", "offset", "items.path", "items.size", "items.md5", "items.sha256", "items.created"}
+	groups := make(map[string]*DuplicateGroup)
+	for offset := 0; ; offset += walkLimit {
+		if e := ctx.Err(); e != nil {
+			return nil, e
+		}
+		l, e := GetFlatFilesListWithOptions(yad, &FilesListOptions{
+			Fields:    fields,
+			Limit:     Int(walkLimit),
+			MediaType: o.MediaType,
+			Offset:    Int(offset),
+			Sort:      SortPath,
+		})
+		if e != nil {
+			return nil, e
+		}
+		for _, r := range l.Items {
+			if r.Size == 0 || r.Size < o.MinSize {
+				continue
+			}
+			key := duplicateKey(o.By, &r)
+			if key == "" {
+				continue
+			}
+			// Files of different sizes never match, even if their hashes collide.
+			id := key + "/" + strconv.FormatInt(r.Size, 10)
+			g, ok := groups[id]
+			if !ok {
+				g = &DuplicateGroup{By: o.By, Key: key, Size: r.Size}
+				groups[id] = g
+			}
+			g.Files = append(g.Files, r)
+		}
+		if len(l.Items) < walkLimit {
+			break
+		}
+	}
+
+	var out []DuplicateGroup
+	for _, g := range groups {
+		if len(g.Files) < 2 {
+			continue
+		}
+		sort.Slice(g.Files, func(i, j int) bool {
+			return g.Files[i].Path < g.Files[j].Path
+		})
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if wi, wj := out[i].Wasted(), out[j].Wasted(); wi != wj {
+			return wi > wj
+		}
+		return out[i].Files[0].Path < out[j].Files[0].Path
+	})
+	return out, nil
+}
+
+// duplicateKey returns the compared property of the file, empty if the file lacks the hash.
+func duplicateKey(by DuplicateKey, r *Resource) string {
+	switch by {
+	case DuplicateSha256:
+		return r.Sha256
+	case DuplicateSize:
+		return strconv.FormatInt(r.Size, 10)
+	default:
+		return r.Md5
+	}
+}
+
+// Options of RemoveDuplicates.
+type DedupeOptions struct {
+	// Choice of the file kept in each group, KeepOldest by default.
+	Keep KeepPolicy
+	// Folders in order of preference for KeepPreferred.
+	Preferred []Path
+	// Delete the duplicates permanently instead of moving them to the Trash.
+	Permanently bool
+	// Only report the files that would be removed.
+	DryRun bool
+	// Interval of WaitOperation, DefaultPollInterval by default.
+	PollInterval time.Duration
+}
+
+// DedupeReport is the result of RemoveDuplicates.
+type DedupeReport struct {
+	// File kept from each group.
+	Kept []Resource
+	// Files removed, or that would be removed on a dry run.
+	Removed []Resource
+	// Bytes freed, or that would be freed on a dry run. Files moved to the Trash still take space until it is cleared.
+	ReclaimedBytes int64
+	// Files that failed to be removed.
+	Failed []CopyFailure
+}
+
+// RemoveDuplicates deletes all the files of each group but the one picked by the keep policy.
+//
+// Groups found by size only are refused, their files may differ. Files are deleted only if their MD5 hash
+// did not change since they were listed.
+func RemoveDuplicates(ctx context.Context, yad YaDisk, groups []DuplicateGroup, opts *DedupeOptions) (*DedupeReport, error) {
+	o := DedupeOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	for i := range groups {
+		if groups[i].By == DuplicateSize {
+			return nil, fmt.Errorf("yadisk: duplicates of size %s are not compared by contents", groups[i].Key)
+		}
+	}
+	report := new(DedupeReport)
+	removed := 0
+	for _, g := range groups {
+		if len(g.Files) < 2 {
+			continue
+		}
+		k := keeper(g.Files, &o)
+		report.Kept = append(report.Kept, g.Files[k])
+		for i, r := range g.Files {
+			if i == k {
+				continue
+			}
+			if o.DryRun {
+				report.Removed = append(report.Removed, r)
+				report.ReclaimedBytes += r.Size
+				continue
+			}
+			removed++
+			if e := removeDuplicate(ctx, yad, &r, &o); e != nil {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				report.Failed = append(report.Failed, CopyFailure{Path: Path(r.Path), Err: e})
+				continue
+			}
+			report.Removed = append(report.Removed, r)
+			report.ReclaimedBytes += r.Size
+		}
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("yadisk: %d of %d duplicates failed to be removed, first: %v",
+			len(report.Failed), removed, report.Failed[0].Err)
+	}
+	return report, nil
+}
+
+func removeDuplicate(ctx context.Context, yad YaDisk, r *Resource, o *DedupeOptions) error {
+	l, e := yad.DeleteResource(r.Path, nil, false, r.Md5, o.Permanently)
+	if e != nil {
+		return e
+	}
+	return WaitOperation(ctx, yad, l, o.PollInterval)
+}
+
+// keeper returns the index of the file to keep.
+func keeper(files []Resource, o *DedupeOptions) int {
+	rank := func(r *Resource) int {
+		if o.Keep != KeepPreferred {
+			return 0
+		}
+		for i, p := range o.Preferred {
+			if Path(r.Path).Within(p) {
+				return i
+			}
+		}
+		return len(o.Preferred)
+	}
+	oldest := func(a, b *Resource) bool {
+		if !a.Created.Equal(b.Created.Time) {
+			return a.Created.Before(b.Created.Time)
+		}
+		if len(a.Path) != len(b.Path) {
+			return len(a.Path) < len(b.Path)
+		}
+		return a.Path < b.Path
+	}
+	k := 0
+	for i := 1; i < len(files); i++ {
+		a, b := &files[i], &files[k]
+		switch {
+		case o.Keep == KeepShortestPath && len(a.Path) != len(b.Path):
+			if len(a.Path) < len(b.Path) {
+				k = i
+			}
+		case o.Keep == KeepShortestPath && a.Path != b.Path:
+			if a.Path < b.Path {
+				k = i
+			}
+		case rank(a) != rank(b):
+			if rank(a) < rank(b) {
+				k = i
+			}
+		case oldest(a, b):
+			k = i
+		}
+	}
+	return k
+}
@@ -0,0 +1,160 @@
+package yadisk_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// newDuplicatesDisk returns a fake with the files created a second apart in the given order.
+func newDuplicatesDisk(t *testing.T) *yadisktest.Disk {
+	now := time.Unix(1000, 0)
+	d := yadisktest.New(&yadisktest.Options{Now: func() time.Time { return now }})
+	files := []struct {
+		path string
+		data string
+	}{
+		{"/photos/backup/long-name.jpg", "photo"},
+		{"/photos/a.jpg", "photo"},
+		{"/inbox/a.jpg", "photo"},
+		{"/docs/report.txt", "report, two copies"},
+		{"/docs/old/report.txt", "report, two copies"},
+		{"/docs/other.txt", "different, size 18"},
+		{"/docs/unique.txt", "unique"},
+		{"/empty1", ""},
+		{"/empty2", ""},
+	}
+	for _, f := range files {
+		if err := d.WriteFile(f.path, []byte(f.data)); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Second)
+	}
+	return d
+}
+
+func groupPaths(groups []yadisk.DuplicateGroup) []string {
+	var got []string
+	for _, g := range groups {
+		s := fmt.Sprintf("%d:", g.Wasted())
+		for _, f := range g.Files {
+			s += " " + string(f.Path)
+		}
+		got = append(got, s)
+	}
+	return got
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *yadisk.DuplicateOptions
+		want []string
+	}{
+		{"md5", nil, []string{
+			"18: disk:/docs/old/report.txt disk:/docs/report.txt",
+			"10: disk:/inbox/a.jpg disk:/photos/a.jpg disk:/photos/backup/long-name.jpg",
+		}},
+		{"sha256", &yadisk.DuplicateOptions{By: yadisk.DuplicateSha256}, []string{
+			"18: disk:/docs/old/report.txt disk:/docs/report.txt",
+			"10: disk:/inbox/a.jpg disk:/photos/a.jpg disk:/photos/backup/long-name.jpg",
+		}},
+		{"size", &yadisk.DuplicateOptions{By: yadisk.DuplicateSize}, []string{
+			"36: disk:/docs/old/report.txt disk:/docs/other.txt disk:/docs/report.txt",
+			"10: disk:/inbox/a.jpg disk:/photos/a.jpg disk:/photos/backup/long-name.jpg",
+		}},
+		{"min_size", &yadisk.DuplicateOptions{MinSize: 10}, []string{
+			"18: disk:/docs/old/report.txt disk:/docs/report.txt",
+		}},
+		{"media_type", &yadisk.DuplicateOptions{MediaType: yadisk.MediaTypeImage}, []string{
+			"10: disk:/inbox/a.jpg disk:/photos/a.jpg disk:/photos/backup/long-name.jpg",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := yadisk.FindDuplicates(context.Background(), newDuplicatesDisk(t), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := groupPaths(groups); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("FindDuplicates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *yadisk.DedupeOptions
+		kept []yadisk.Path
+	}{
+		{"oldest", nil, []yadisk.Path{"disk:/docs/report.txt", "disk:/photos/backup/long-name.jpg"}},
+		{"shortest_path", &yadisk.DedupeOptions{Keep: yadisk.KeepShortestPath},
+			[]yadisk.Path{"disk:/docs/report.txt", "disk:/inbox/a.jpg"}},
+		{"preferred", &yadisk.DedupeOptions{Keep: yadisk.KeepPreferred, Preferred: []yadisk.Path{"/docs/old", "/photos"}},
+			[]yadisk.Path{"disk:/docs/old/report.txt", "disk:/photos/backup/long-name.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := newDuplicatesDisk(t)
+			groups, err := yadisk.FindDuplicates(ctx, d, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := yadisk.RemoveDuplicates(ctx, d, groups, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kept []yadisk.Path
+			for _, r := range report.Kept {
+				kept = append(kept, yadisk.Path(r.Path))
+			}
+			if fmt.Sprint(kept) != fmt.Sprint(tt.kept) {
+				t.Errorf("RemoveDuplicates() kept %v, want %v", kept, tt.kept)
+			}
+			if len(report.Removed) != 3 || report.ReclaimedBytes != 28 {
+				t.Errorf("RemoveDuplicates() removed %d files of %d bytes, want 3 of 28", len(report.Removed), report.ReclaimedBytes)
+			}
+			if groups, _ := yadisk.FindDuplicates(ctx, d, nil); len(groups) != 0 {
+				t.Errorf("FindDuplicates() after removal = %q", groupPaths(groups))
+			}
+			for _, p := range tt.kept {
+				if _, err := d.GetResource(string(p), nil, 0, 0, false, "", ""); err != nil {
+					t.Errorf("kept file %s: %v", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveDuplicates_safety(t *testing.T) {
+	ctx := context.Background()
+	d := newDuplicatesDisk(t)
+
+	groups, _ := yadisk.FindDuplicates(ctx, d, &yadisk.DuplicateOptions{By: yadisk.DuplicateSize})
+	if _, err := yadisk.RemoveDuplicates(ctx, d, groups, nil); err == nil {
+		t.Error("RemoveDuplicates() of groups by size error = nil")
+	}
+
+	groups, _ = yadisk.FindDuplicates(ctx, d, nil)
+	report, err := yadisk.RemoveDuplicates(ctx, d, groups, &yadisk.DedupeOptions{DryRun: true})
+	if err != nil || len(report.Removed) != 3 {
+		t.Fatalf("RemoveDuplicates() dry run = %+v, error = %v", report, err)
+	}
+	if after, _ := yadisk.FindDuplicates(ctx, d, nil); len(after) != 2 {
+		t.Errorf("FindDuplicates() after a dry run = %q", groupPaths(after))
+	}
+
+	// A file changed since it was listed is not deleted.
+	_ = d.WriteFile("/docs/old/report.txt", []byte("report, changed now"))
+	report, err = yadisk.RemoveDuplicates(ctx, d, groups, nil)
+	if err == nil || len(report.Failed) != 1 || report.Failed[0].Path != "disk:/docs/old/report.txt" {
+		t.Errorf("RemoveDuplicates() failed = %v, error = %v", report.Failed, err)
+	}
+}
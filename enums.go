@@ -0,0 +1,163 @@
+package yadisk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SortField is a key to sort a list of resources by. Prefixed with "-" it sorts in descending order.
+type SortField string
+
+const (
+	SortName     SortField = "name"
+	SortPath     SortField = "path"
+	SortCreated  SortField = "created"
+	SortModified SortField = "modified"
+	SortSize     SortField = "size"
+	// Only for the contents of the Trash.
+	SortDeleted SortField = "deleted"
+)
+
+// Desc returns the field sorting in descending order.
+func (s SortField) Desc() SortField {
+	return "-" + s.Field()
+}
+
+// IsDesc reports whether s sorts in descending order.
+func (s SortField) IsDesc() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// Field returns s without the descending modifier.
+func (s SortField) Field() SortField {
+	return SortField(strings.TrimPrefix(string(s), "-"))
+}
+
+// Validate returns an error if s is neither empty nor a documented sort field.
+func (s SortField) Validate() error {
+	switch s.Field() {
+	case SortName, SortPath, SortCreated, SortModified, SortSize, SortDeleted:
+		return nil
+	case "":
+		if s == "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("yadisk: unknown sort field %q", string(s))
+}
+
+// MediaType is the category of a file detected by the API.
+type MediaType string
+
+const (
+	MediaTypeAudio       MediaType = "audio"
+	MediaTypeBackup      MediaType = "backup"
+	MediaTypeBook        MediaType = "book"
+	MediaTypeCompressed  MediaType = "compressed"
+	MediaTypeData        MediaType = "data"
+	MediaTypeDevelopment MediaType = "development"
+	MediaTypeDiskImage   MediaType = "diskimage"
+	MediaTypeDocument    MediaType = "document"
+	MediaTypeEncoded     MediaType = "encoded"
+	MediaTypeExecutable  MediaType = "executable"
+	MediaTypeFlash       MediaType = "flash"
+	MediaTypeFont        MediaType = "font"
+	MediaTypeImage       MediaType = "image"
+	MediaTypeSettings    MediaType = "settings"
+	MediaTypeSpreadsheet MediaType = "spreadsheet"
+	MediaTypeText        MediaType = "text"
+	MediaTypeUnknown     MediaType = "unknown"
+	MediaTypeVideo       MediaType = "video"
+	MediaTypeWeb         MediaType = "web"
+)
+
+var mediaTypes = map[MediaType]bool{
+	MediaTypeAudio: true, MediaTypeBackup: true, MediaTypeBook: true, MediaTypeCompressed: true,
+	MediaTypeData: true, MediaTypeDevelopment: true, MediaTypeDiskImage: true, MediaTypeDocument: true,
+	MediaTypeEncoded: true, MediaTypeExecutable: true, MediaTypeFlash: true, MediaTypeFont: true,
+	MediaTypeImage: true, MediaTypeSettings: true, MediaTypeSpreadsheet: true, MediaTypeText: true,
+	MediaTypeUnknown: true, MediaTypeVideo: true, MediaTypeWeb: true,
+}
+
+// MediaTypes joins several media types into one filter value.
+func MediaTypes(types ...MediaType) MediaType {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return MediaType(strings.Join(s, ","))
+}
+
+// Validate returns an error if m is neither empty nor a comma-separated list of documented media types.
+func (m MediaType) Validate() error {
+	if m == "" {
+		return nil
+	}
+	for _, t := range strings.Split(string(m), ",") {
+		if !mediaTypes[MediaType(t)] {
+			return fmt.Errorf("yadisk: unknown media type %q", t)
+		}
+	}
+	return nil
+}
+
+// PreviewSize is the size of the preview images returned in Resource.Preview.
+type PreviewSize string
+
+const (
+	PreviewSizeS    PreviewSize = "S"
+	PreviewSizeM    PreviewSize = "M"
+	PreviewSizeL    PreviewSize = "L"
+	PreviewSizeXL   PreviewSize = "XL"
+	PreviewSizeXXL  PreviewSize = "XXL"
+	PreviewSizeXXXL PreviewSize = "XXXL"
+)
+
+var previewSizeRegexp = regexp.MustCompile(`^(S|M|L|XL|XXL|XXXL|\d+|\d+x|x\d+|\d+x\d+)$`)
+
+// PreviewSizeWH returns a preview size in pixels. A zero width or height keeps the proportions.
+func PreviewSizeWH(width int, height int) PreviewSize {
+	switch {
+	case height == 0:
+		return PreviewSize(fmt.Sprintf("%dx", width))
+	case width == 0:
+		return PreviewSize(fmt.Sprintf("x%d", height))
+	}
+	return PreviewSize(fmt.Sprintf("%dx%d", width, height))
+}
+
+// Validate returns an error if p is neither empty, a predefined size nor a size in pixels.
+func (p PreviewSize) Validate() error {
+	if p == "" || previewSizeRegexp.MatchString(string(p)) {
+		return nil
+	}
+	return fmt.Errorf("yadisk: invalid preview size %q", string(p))
+}
+
+// ResourceType is the type of a resource, a file or a folder.
+type ResourceType string
+
+const (
+	ResourceTypeDir  ResourceType = "dir"
+	ResourceTypeFile ResourceType = "file"
+)
+
+// Validate returns an error if t is neither empty, "dir" nor "file".
+func (t ResourceType) Validate() error {
+	switch t {
+	case "", ResourceTypeDir, ResourceTypeFile:
+		return nil
+	}
+	return fmt.Errorf("yadisk: unknown resource type %q", string(t))
+}
+
+// validate returns the first error of the checks.
+func validate(errs ...error) error {
+	for _, e := range errs {
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
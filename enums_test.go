@@ -0,0 +1,92 @@
+package yadisk
+
+import "testing"
+
+func TestSortField(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       SortField
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"name", SortName, false},
+		{"desc", SortModified.Desc(), false},
+		{"desc_twice", SortModified.Desc().Desc(), false},
+		{"only_modifier", "-", true},
+		{"typo", "modifed", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SortField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if got := SortSize.Desc(); got != "-size" || !got.IsDesc() || got.Field() != SortSize {
+		t.Errorf("SortField.Desc() = %v", got)
+	}
+}
+
+func TestMediaType_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       MediaType
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"one", MediaTypeDiskImage, false},
+		{"many", MediaTypes(MediaTypeImage, MediaTypeVideo), false},
+		{"typo", "imgae", true},
+		{"many_typo", MediaTypes(MediaTypeImage, "vidoe"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MediaType.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreviewSize_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       PreviewSize
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"predefined", PreviewSizeXXXL, false},
+		{"width_height", PreviewSizeWH(120, 240), false},
+		{"width", PreviewSizeWH(120, 0), false},
+		{"height", PreviewSizeWH(0, 240), false},
+		{"lowercase", "xl", true},
+		{"bad", "120y240", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PreviewSize.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceType_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rt      ResourceType
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"dir", ResourceTypeDir, false},
+		{"file", ResourceTypeFile, false},
+		{"folder", "folder", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rt.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceType.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package yadisk
+
+// Error identifiers returned by the Yandex.Disk API in Error.ErrorID.
+const (
+	ErrorIDFieldValidation         = "FieldValidationError"
+	ErrorIDUnauthorized            = "UnauthorizedError"
+	ErrorIDNotFound                = "DiskNotFoundError"
+	ErrorIDOperationNotFound       = "DiskOperationNotFoundError"
+	ErrorIDPathDoesntExist         = "DiskPathDoesntExistsError"
+	ErrorIDPathPointsToExistentDir = "DiskPathPointsToExistentDirectoryError"
+	ErrorIDResourceAlreadyExists   = "DiskResourceAlreadyExistsError"
+	ErrorIDMD5Differ               = "MD5DifferError"
+	ErrorIDFileTooLarge            = "DiskFileTooLargeError"
+	ErrorIDTooManyRequests         = "TooManyRequestsError"
+	ErrorIDStorageQuotaExhausted   = "DiskStorageQuotaExhaustedError"
+)
+
+// IsErrorID reports whether e is an API *Error with the given ErrorID.
+func IsErrorID(e error, errorID string) bool {
+	err, ok := e.(*Error)
+	return ok && err.ErrorID == errorID
+}
@@ -0,0 +1,136 @@
+package yadisk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Minimal fields of a resource and its listing, enough to walk or sync a tree.
+var MinimalResourceFields = []string{
+	"name", "path", "type", "size", "md5", "sha256", "modified", "resource_id", "revision",
+	"_embedded.items.name", "_embedded.items.path", "_embedded.items.type", "_embedded.items.size",
+	"_embedded.items.md5", "_embedded.items.sha256", "_embedded.items.modified", "_embedded.items.resource_id",
+	"_embedded.limit", "_embedded.offset", "_embedded.total",
+}
+
+// Minimal fields of a flat or last uploaded files list, enough to walk or sync a tree.
+var MinimalFilesListFields = []string{
+	"items.name", "items.path", "items.type", "items.size", "items.md5", "items.sha256",
+	"items.modified", "items.resource_id",
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// FieldsBuilder builds the fields parameter of a request, checking each name against the JSON tags of the response type.
+type FieldsBuilder struct {
+	typ    reflect.Type
+	fields []string
+	err    error
+}
+
+// NewFields returns a builder for the response type of v, e.g. Resource{} or (*FilesResourceList)(nil).
+func NewFields(v interface{}) *FieldsBuilder {
+	return &FieldsBuilder{typ: reflect.TypeOf(v)}
+}
+
+// Add appends dot-separated paths such as "_embedded.items.name". The first unknown path is reported by Build.
+func (f *FieldsBuilder) Add(paths ...string) *FieldsBuilder {
+	for _, p := range paths {
+		if f.err == nil {
+			f.err = validateField(f.typ, p)
+		}
+		f.fields = append(f.fields, p)
+	}
+	return f
+}
+
+// Embedded appends paths relative to the items of the _embedded listing, e.g. Embedded("name") adds "_embedded.items.name".
+func (f *FieldsBuilder) Embedded(paths ...string) *FieldsBuilder {
+	for _, p := range paths {
+		f.Add("_embedded.items." + p)
+	}
+	return f
+}
+
+// Build returns the fields or the first validation error.
+func (f *FieldsBuilder) Build() ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.fields, nil
+}
+
+// MustBuild is like Build but panics on an unknown path.
+func (f *FieldsBuilder) MustBuild() []string {
+	fields, e := f.Build()
+	if e != nil {
+		panic(e)
+	}
+	return fields
+}
+
+// ValidateFields returns an error if one of the fields does not exist on the response type of v.
+func ValidateFields(v interface{}, fields []string) error {
+	_, e := NewFields(v).Add(fields...).Build()
+	return e
+}
+
+func validateField(typ reflect.Type, path string) error {
+	t := typ
+	for _, name := range strings.Split(path, ".") {
+		t = elem(t)
+		if t != nil && t.Kind() == reflect.Interface {
+			// Free-form values such as custom properties.
+			return nil
+		}
+		if t == nil || t.Kind() != reflect.Struct || decodesItself(t) || name == "" {
+			return fmt.Errorf("yadisk: unknown field %q of %v", path, typ)
+		}
+		t = jsonField(t, name)
+	}
+	if t == nil {
+		return fmt.Errorf("yadisk: unknown field %q of %v", path, typ)
+	}
+	return nil
+}
+
+// decodesItself reports whether t is decoded by its own UnmarshalJSON, as Time is, so it has no fields to select.
+func decodesItself(t reflect.Type) bool {
+	return t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)
+}
+
+// elem dereferences pointers, slices and arrays, as the API applies fields to each element of an array.
+func elem(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	return t
+}
+
+// jsonField returns the type of the field encoded as name, looking into embedded structs.
+func jsonField(t reflect.Type, name string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			if ft := elem(f.Type); ft.Kind() == reflect.Struct {
+				if found := jsonField(ft, name); found != nil {
+					return found
+				}
+				continue
+			}
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return f.Type
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package yadisk
+
+import "testing"
+
+func TestValidateFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		fields  []string
+		wantErr bool
+	}{
+		{"empty", Resource{}, nil, false},
+		{"top_level", Resource{}, []string{"name", "size", "path"}, false},
+		{"embedded_struct", Resource{}, []string{"resource_id", "exif.date_time", "share.is_root"}, false},
+		{"nested_items", Resource{}, []string{"_embedded.items.name", "_embedded.total"}, false},
+		{"custom_properties", Resource{}, []string{"custom_properties.foo.bar"}, false},
+		{"pointer", (*Disk)(nil), []string{"user.login", "system_folders.downloads", "total_space"}, false},
+		{"trash", TrashResource{}, []string{"_embedded.items.origin_path", "deleted"}, false},
+		{"public", PublicResource{}, []string{"owner.login", "views_count", "_embedded.items.views_count"}, false},
+		{"list", FilesResourceList{}, []string{"items.md5", "offset"}, false},
+		{"unknown", Resource{}, []string{"nmae"}, true},
+		{"unknown_nested", Resource{}, []string{"_embedded.items.nmae"}, true},
+		{"into_scalar", Resource{}, []string{"name.first"}, true},
+		{"into_time", Resource{}, []string{"created.year"}, true},
+		{"into_time_wall", Resource{}, []string{"created.wall"}, true},
+		{"into_time_ext", Resource{}, []string{"modified.ext"}, true},
+		{"into_time_field", Resource{}, []string{"modified.Time"}, true},
+		{"into_exif_time", Resource{}, []string{"exif.date_time.wall"}, true},
+		{"time_wrapper", Resource{}, []string{"created", "exif.date_time"}, false},
+		{"empty_segment", Resource{}, []string{"_embedded..name"}, true},
+		{"nil", nil, []string{"name"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFields(tt.v, tt.fields); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldsBuilder(t *testing.T) {
+	got, err := NewFields(Resource{}).Add("path", "type").Embedded("name", "md5").Build()
+	if err != nil || len(got) != 4 || got[3] != "_embedded.items.md5" {
+		t.Errorf("FieldsBuilder.Build() = %v, error = %v", got, err)
+	}
+	if _, err := NewFields(Resource{}).Add("nmae").Embedded("name").Build(); err == nil {
+		t.Errorf("FieldsBuilder.Build() error = %v, wantErr true", err)
+	}
+	if err := ValidateFields(Resource{}, MinimalResourceFields); err != nil {
+		t.Errorf("MinimalResourceFields error = %v", err)
+	}
+	for _, v := range []interface{}{FilesResourceList{}, LastUploadedResourceList{}} {
+		if err := ValidateFields(v, MinimalFilesListFields); err != nil {
+			t.Errorf("MinimalFilesListFields error = %v", err)
+		}
+	}
+}
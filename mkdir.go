@@ -0,0 +1,81 @@
+package yadisk
+
+import (
+	"context"
+	"sync"
+)
+
+// DirCache creates folders and remembers the ones known to exist, so repeated calls cost no requests.
+type DirCache struct {
+	yad  YaDisk
+	mu   sync.Mutex
+	dirs map[Path]bool
+}
+
+// NewDirCache returns an empty cache of the folders of yad.
+func NewDirCache(yad YaDisk) *DirCache {
+	return &DirCache{yad: yad, dirs: make(map[Path]bool)}
+}
+
+// MkdirAll creates the folder at p together with the missing parents. Existing folders are not an error.
+func MkdirAll(ctx context.Context, yad YaDisk, p Path) error {
+	return NewDirCache(yad).MkdirAll(ctx, p)
+}
+
+// MkdirAll creates the folder at p together with the missing parents. Existing folders are not an error.
+func (c *DirCache) MkdirAll(ctx context.Context, p Path) error {
+	return c.mkdirAll(ctx, p.Clean())
+}
+
+func (c *DirCache) mkdirAll(ctx context.Context, dir Path) error {
+	if c.exists(dir) {
+		return nil
+	}
+	if e := ctx.Err(); e != nil {
+		return e
+	}
+	_, e := c.yad.CreateResource(string(dir), []string{"path"})
+	switch {
+	case e == nil, IsErrorID(e, ErrorIDPathPointsToExistentDir):
+	case IsErrorID(e, ErrorIDPathDoesntExist):
+		if e := c.mkdirAll(ctx, dir.Dir()); e != nil {
+			return e
+		}
+		if _, e := c.yad.CreateResource(string(dir), []string{"path"}); e != nil && !IsErrorID(e, ErrorIDPathPointsToExistentDir) {
+			return e
+		}
+	default:
+		return e
+	}
+	c.confirm(dir)
+	return nil
+}
+
+// Forget drops p and the folders below it from the cache, e.g. after they were deleted or moved.
+func (c *DirCache) Forget(p Path) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for d := range c.dirs {
+		if d.Within(p) {
+			delete(c.dirs, d)
+		}
+	}
+}
+
+func (c *DirCache) exists(dir Path) bool {
+	if dir.IsRoot() {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dirs[dir]
+}
+
+// confirm caches dir and its parents.
+func (c *DirCache) confirm(dir Path) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ; !dir.IsRoot(); dir = dir.Dir() {
+		c.dirs[dir] = true
+	}
+}
@@ -0,0 +1,65 @@
+package yadisk_test
+
+import (
+	"context"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+type countingDisk struct {
+	yadisk.YaDisk
+	creates int
+}
+
+func (c *countingDisk) CreateResource(path string, fields []string) (*yadisk.Link, error) {
+	c.creates++
+	return c.YaDisk.CreateResource(path, fields)
+}
+
+func TestDirCache_MkdirAll(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/file", []byte("x"))
+	yad := &countingDisk{YaDisk: d}
+	c := yadisk.NewDirCache(yad)
+
+	tests := []struct {
+		name    string
+		path    yadisk.Path
+		creates int
+		wantErr bool
+	}{
+		{"nested", "/a/b/c", 5, false},
+		{"cached", "disk:/a/b/c/", 0, false},
+		{"cached_parent", "/a/b", 0, false},
+		{"sibling", "/a/b/d", 1, false},
+		{"root", "/", 0, false},
+		{"file_in_the_way", "/file/x", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yad.creates = 0
+			if err := c.MkdirAll(ctx, tt.path); (err != nil) != tt.wantErr {
+				t.Fatalf("DirCache.MkdirAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if yad.creates != tt.creates {
+				t.Errorf("DirCache.MkdirAll() creates = %d, want %d", yad.creates, tt.creates)
+			}
+		})
+	}
+	if r, err := d.GetResource("/a/b/c", nil, 0, 0, false, "", ""); err != nil || r.Type != yadisk.ResourceTypeDir {
+		t.Errorf("GetResource() = %v, error = %v", r, err)
+	}
+
+	// An existing folder unknown to a new cache is not an error.
+	if err := yadisk.MkdirAll(ctx, yad, "/a/b"); err != nil {
+		t.Errorf("MkdirAll() error = %v", err)
+	}
+	c.Forget("/a/b")
+	yad.creates = 0
+	if err := c.MkdirAll(ctx, "/a/b/c"); err != nil || yad.creates != 1 {
+		t.Errorf("DirCache.MkdirAll() after Forget error = %v, creates = %d", err, yad.creates)
+	}
+}
@@ -1,15 +1,29 @@
 package yadisk
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
+// Statuses of an asynchronous operation.
+const (
+	OperationStatusSuccess    = "success"
+	OperationStatusFailed     = "failed"
+	OperationStatusInProgress = "in-progress"
+)
+
+// Interval between the status requests of WaitOperation when none is given.
+const DefaultPollInterval = time.Second
+
 // Get the status of an asynchronous operation.
 func (yad *yandexDisk) GetOperationStatus(operationID string, fields []string) (s *OperationStatus, e error) {
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
+	addFields(values, fields)
 
 	req, e := yad.client.request(http.MethodGet, "/disk/operations/"+operationID+"?"+values.Encode(), nil)
 	if e != nil {
@@ -23,3 +37,51 @@ func (yad *yandexDisk) GetOperationStatus(operationID string, fields []string) (
 	}
 	return
 }
+
+// OperationID returns the ID of the asynchronous operation of the link, ok is false if the link points to a resource.
+func OperationID(l *Link) (id string, ok bool) {
+	if l == nil {
+		return "", false
+	}
+	u, e := url.Parse(l.Href)
+	if e != nil {
+		return "", false
+	}
+	i := strings.LastIndex(u.Path, "/operations/")
+	if i < 0 || i+len("/operations/") == len(u.Path) {
+		return "", false
+	}
+	return u.Path[i+len("/operations/"):], true
+}
+
+// WaitOperation polls the status of the operation of the link until it finishes.
+// A link to a resource, returned when the call completed synchronously, is done already.
+func WaitOperation(ctx context.Context, yad YaDisk, l *Link, interval time.Duration) error {
+	id, ok := OperationID(l)
+	if !ok {
+		return nil
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	for {
+		s, e := yad.GetOperationStatus(id, nil)
+		if e != nil {
+			return e
+		}
+		switch s.Status {
+		case OperationStatusSuccess:
+			return nil
+		case OperationStatusFailed:
+			return errors.New("yadisk: operation " + id + " failed")
+		case OperationStatusInProgress:
+		default:
+			return fmt.Errorf("yadisk: unknown status %q of operation %s", s.Status, id)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package yadisk
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Options of GetResourceWithOptions and GetTrashResourceWithOptions.
+//
+// Unset values are left out of the query string, so the server defaults apply.
+type ResourceOptions struct {
+	Fields      []string
+	Limit       *int
+	Offset      *int
+	PreviewCrop bool
+	PreviewSize PreviewSize
+	Sort        SortField
+}
+
+// Options of GetPublicResourceWithOptions.
+//
+// Unset values are left out of the query string, so the server defaults apply.
+type PublicResourceOptions struct {
+	Fields []string
+	Limit  *int
+	Offset *int
+	// Path to a resource inside the public folder.
+	Path        string
+	PreviewCrop bool
+	PreviewSize PreviewSize
+	Sort        SortField
+}
+
+// Options of GetFlatFilesListWithOptions.
+//
+// Unset values are left out of the query string, so the server defaults apply.
+type FilesListOptions struct {
+	Fields      []string
+	Limit       *int
+	MediaType   MediaType
+	Offset      *int
+	PreviewCrop bool
+	PreviewSize PreviewSize
+	Sort        SortField
+}
+
+// Options of GetLastUploadedFilesListWithOptions.
+//
+// Unset values are left out of the query string, so the server defaults apply.
+type LastUploadedOptions struct {
+	Fields      []string
+	Limit       *int
+	MediaType   MediaType
+	PreviewCrop bool
+	PreviewSize PreviewSize
+}
+
+// Options of ListPublicResourcesWithOptions.
+//
+// Unset values are left out of the query string, so the server defaults apply.
+type PublicResourcesListOptions struct {
+	Fields      []string
+	Limit       *int
+	Offset      *int
+	PreviewCrop bool
+	PreviewSize PreviewSize
+	// Resource type value: "","dir","file".
+	Type ResourceType
+}
+
+// Options of ClearTrashWithOptions.
+type ClearTrashOptions struct {
+	Fields     []string
+	ForceAsync bool
+	// Resource to delete from the Trash, the whole Trash is emptied if empty.
+	Path string
+}
+
+// Options of RestoreFromTrashWithOptions.
+type RestoreFromTrashOptions struct {
+	Fields     []string
+	ForceAsync bool
+	// New name of the restored resource.
+	Name      string
+	Overwrite bool
+}
+
+// Options of DeleteResourceWithOptions.
+type DeleteOptions struct {
+	Fields     []string
+	ForceAsync bool
+	// The file is deleted only if its MD5 hash matches.
+	Md5 string
+	// Delete without placing the resource in the Trash.
+	Permanently bool
+}
+
+// Options of CopyResourceWithOptions and MoveResourceWithOptions.
+type TransferOptions struct {
+	Fields     []string
+	ForceAsync bool
+	Overwrite  bool
+}
+
+// Options of UploadExternalResourceWithOptions.
+type UploadExternalOptions struct {
+	DisableRedirects bool
+	Fields           []string
+}
+
+// Options of SaveToDiskPublicResourceWithOptions.
+type SaveToDiskOptions struct {
+	Fields     []string
+	ForceAsync bool
+	// Name of the saved copy.
+	Name string
+	// Path to a resource inside the public folder.
+	Path string
+	// Folder to save to, the Downloads folder if empty.
+	SavePath string
+}
+
+// GetTrashResourceWithOptions gets the contents of the Trash with the options, see YaDiskWithOptions.
+func GetTrashResourceWithOptions(yad YaDisk, path string, opts *ResourceOptions) (*TrashResource, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.GetTrashResourceWithOptions(path, opts)
+	}
+	o := ResourceOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.GetTrashResource(path, o.Fields, intValue(o.Limit), intValue(o.Offset), o.PreviewCrop, string(o.PreviewSize), string(o.Sort))
+}
+
+// GetResourceWithOptions gets meta information about a file or directory with the options, see YaDiskWithOptions.
+func GetResourceWithOptions(yad YaDisk, path string, opts *ResourceOptions) (*Resource, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.GetResourceWithOptions(path, opts)
+	}
+	o := ResourceOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.GetResource(path, o.Fields, intValue(o.Limit), intValue(o.Offset), o.PreviewCrop, string(o.PreviewSize), string(o.Sort))
+}
+
+// GetFlatFilesListWithOptions gets the file list with the options, see YaDiskWithOptions.
+func GetFlatFilesListWithOptions(yad YaDisk, opts *FilesListOptions) (*FilesResourceList, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.GetFlatFilesListWithOptions(opts)
+	}
+	o := FilesListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.GetFlatFilesList(o.Fields, intValue(o.Limit), string(o.MediaType), intValue(o.Offset), o.PreviewCrop, string(o.PreviewSize), string(o.Sort))
+}
+
+// GetLastUploadedFilesListWithOptions gets the files ordered by upload date with the options, see YaDiskWithOptions.
+func GetLastUploadedFilesListWithOptions(yad YaDisk, opts *LastUploadedOptions) (*LastUploadedResourceList, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.GetLastUploadedFilesListWithOptions(opts)
+	}
+	o := LastUploadedOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.GetLastUploadedFilesList(o.Fields, intValue(o.Limit), string(o.MediaType), o.PreviewCrop, string(o.PreviewSize))
+}
+
+// ListPublicResourcesWithOptions gets the published resources with the options, see YaDiskWithOptions.
+func ListPublicResourcesWithOptions(yad YaDisk, opts *PublicResourcesListOptions) (*PublicResourcesList, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.ListPublicResourcesWithOptions(opts)
+	}
+	o := PublicResourcesListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.ListPublicResources(o.Fields, intValue(o.Limit), intValue(o.Offset), o.PreviewCrop, string(o.PreviewSize), string(o.Type))
+}
+
+// GetPublicResourceWithOptions gets meta-information about a public resource with the options, see YaDiskWithOptions.
+func GetPublicResourceWithOptions(yad YaDisk, publicKey string, opts *PublicResourceOptions) (*PublicResource, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.GetPublicResourceWithOptions(publicKey, opts)
+	}
+	o := PublicResourceOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if e := o.validate(); e != nil {
+		return nil, e
+	}
+	return yad.GetPublicResource(publicKey, o.Fields, intValue(o.Limit), intValue(o.Offset), o.Path, o.PreviewCrop, string(o.PreviewSize), string(o.Sort))
+}
+
+// ClearTrashWithOptions empties the Trash with the options, see YaDiskWithOptions.
+func ClearTrashWithOptions(yad YaDisk, opts *ClearTrashOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.ClearTrashWithOptions(opts)
+	}
+	o := ClearTrashOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.ClearTrash(o.Fields, o.ForceAsync, o.Path)
+}
+
+// RestoreFromTrashWithOptions recovers a resource from the Trash with the options, see YaDiskWithOptions.
+func RestoreFromTrashWithOptions(yad YaDisk, path string, opts *RestoreFromTrashOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.RestoreFromTrashWithOptions(path, opts)
+	}
+	o := RestoreFromTrashOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.RestoreFromTrash(path, o.Fields, o.ForceAsync, o.Name, o.Overwrite)
+}
+
+// DeleteResourceWithOptions deletes a file or folder with the options, see YaDiskWithOptions.
+func DeleteResourceWithOptions(yad YaDisk, path string, opts *DeleteOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.DeleteResourceWithOptions(path, opts)
+	}
+	o := DeleteOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.DeleteResource(path, o.Fields, o.ForceAsync, o.Md5, o.Permanently)
+}
+
+// CopyResourceWithOptions copies a file or folder with the options, see YaDiskWithOptions.
+func CopyResourceWithOptions(yad YaDisk, from string, path string, opts *TransferOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.CopyResourceWithOptions(from, path, opts)
+	}
+	o := TransferOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.CopyResource(from, path, o.Fields, o.ForceAsync, o.Overwrite)
+}
+
+// MoveResourceWithOptions moves a file or folder with the options, see YaDiskWithOptions.
+func MoveResourceWithOptions(yad YaDisk, from string, path string, opts *TransferOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.MoveResourceWithOptions(from, path, opts)
+	}
+	o := TransferOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.MoveResource(from, path, o.Fields, o.ForceAsync, o.Overwrite)
+}
+
+// UploadExternalResourceWithOptions uploads a file to Disk by URL with the options, see YaDiskWithOptions.
+func UploadExternalResourceWithOptions(yad YaDisk, path string, externalURL string, opts *UploadExternalOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.UploadExternalResourceWithOptions(path, externalURL, opts)
+	}
+	o := UploadExternalOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.UploadExternalResource(path, externalURL, o.DisableRedirects, o.Fields)
+}
+
+// SaveToDiskPublicResourceWithOptions saves a public resource to the Disk with the options, see YaDiskWithOptions.
+func SaveToDiskPublicResourceWithOptions(yad YaDisk, publicKey string, opts *SaveToDiskOptions) (*Link, error) {
+	if y, ok := yad.(YaDiskWithOptions); ok {
+		return y.SaveToDiskPublicResourceWithOptions(publicKey, opts)
+	}
+	o := SaveToDiskOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return yad.SaveToDiskPublicResource(publicKey, o.Fields, o.ForceAsync, o.Name, o.Path, o.SavePath)
+}
+
+// validate checks the typed values of the options. Only the option methods do, the positional ones send any value.
+func (o *ResourceOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	return validate(o.PreviewSize.Validate(), o.Sort.Validate())
+}
+
+func (o *PublicResourceOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	return validate(o.PreviewSize.Validate(), o.Sort.Validate())
+}
+
+func (o *FilesListOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	return validate(o.MediaType.Validate(), o.PreviewSize.Validate(), o.Sort.Validate())
+}
+
+func (o *LastUploadedOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	return validate(o.MediaType.Validate(), o.PreviewSize.Validate())
+}
+
+func (o *PublicResourcesListOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	return validate(o.PreviewSize.Validate(), o.Type.Validate())
+}
+
+// Int returns a pointer to v, to set Limit or Offset.
+func Int(v int) *int {
+	return &v
+}
+
+// nonZero returns a pointer to v, nil if it is zero. The positional methods cannot tell zero from unset.
+func nonZero(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// intValue returns the value of p, zero if it is nil.
+func intValue(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func addValue(values url.Values, key string, value string) {
+	if value != "" {
+		values.Add(key, value)
+	}
+}
+
+func addInt(values url.Values, key string, value *int) {
+	if value != nil {
+		values.Add(key, strconv.Itoa(*value))
+	}
+}
+
+func addBool(values url.Values, key string, value bool) {
+	if value {
+		values.Add(key, strconv.FormatBool(value))
+	}
+}
+
+func addFields(values url.Values, fields []string) {
+	if len(fields) > 0 {
+		values.Add("fields", strings.Join(fields, ","))
+	}
+}
@@ -0,0 +1,94 @@
+package yadisk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_yandexDisk_options_query(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c, _ := newClient(context.Background(), &testValidToken, srv.URL, 1, nil)
+	yad := &yandexDisk{Token: &testValidToken, client: c}
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"positional_zero", func() error {
+			_, e := yad.GetResource("/a", nil, 0, 0, false, "", "")
+			return e
+		}, "path=%2Fa"},
+		{"options", func() error {
+			_, e := yad.GetResourceWithOptions("/a", &ResourceOptions{Fields: []string{"name", "size"}, Limit: Int(5), Sort: "-size"})
+			return e
+		}, "fields=name%2Csize&limit=5&path=%2Fa&sort=-size"},
+		{"zero_limit", func() error {
+			_, e := yad.GetResourceWithOptions("/a", &ResourceOptions{Limit: Int(0), Offset: Int(0)})
+			return e
+		}, "limit=0&offset=0&path=%2Fa"},
+		{"nil_options", func() error {
+			_, e := yad.GetFlatFilesListWithOptions(nil)
+			return e
+		}, ""},
+		{"public", func() error {
+			_, e := yad.GetPublicResourceWithOptions("key", &PublicResourceOptions{Offset: Int(20), Path: "/sub", PreviewCrop: true})
+			return e
+		}, "offset=20&path=%2Fsub&preview_crop=true&public_key=key"},
+		{"bools", func() error {
+			_, e := yad.CopyResource("/a", "/b", nil, false, true)
+			return e
+		}, "from=%2Fa&overwrite=true&path=%2Fb"},
+		{"delete", func() error {
+			_, e := DeleteResourceWithOptions(yad, "/a", &DeleteOptions{Permanently: true})
+			return e
+		}, "path=%2Fa&permanently=true"},
+		{"fallback", func() error {
+			// Only the positional methods of YaDisk are visible through the wrapper.
+			_, e := GetResourceWithOptions(struct{ YaDisk }{yad}, "/a", &ResourceOptions{Limit: Int(5)})
+			return e
+		}, "limit=5&path=%2Fa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if query != tt.want {
+				t.Errorf("query = %v, want %v", query, tt.want)
+			}
+		})
+	}
+}
+
+func Test_yandexDisk_options_validation(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c, _ := newClient(context.Background(), &testValidToken, srv.URL, 1, nil)
+	yad := &yandexDisk{Token: &testValidToken, client: c}
+
+	if _, err := yad.GetResource("/a", nil, 0, 0, false, "", "nmae"); err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	if query != "path=%2Fa&sort=nmae" {
+		t.Errorf("GetResource() query = %v, want the sort sent as is", query)
+	}
+	query = ""
+	if _, err := yad.GetResourceWithOptions("/a", &ResourceOptions{Sort: "nmae"}); err == nil {
+		t.Error("GetResourceWithOptions() error = nil, want an unknown sort field")
+	}
+	if query != "" {
+		t.Errorf("GetResourceWithOptions() sent %v", query)
+	}
+}
@@ -0,0 +1,190 @@
+package yadisk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Root is the namespace of a path.
+type Root string
+
+const (
+	// Root of the user's Disk, the namespace of paths without a prefix.
+	RootDisk Root = "disk:"
+	// Root of the application folder, disk:/Applications/<app name>.
+	RootApp Root = "app:"
+	// Root of the Trash.
+	RootTrash Root = "trash:"
+)
+
+// Path is the path of a resource, such as "disk:/photos/cat.jpg", "/photos/cat.jpg" or "app:/settings.json".
+//
+// Paths returned by ParsePath and the methods of Path are clean, with an explicit root.
+type Path string
+
+// ParsePath returns the clean form of s. A path without a root is a path on the Disk.
+func ParsePath(s string) (Path, error) {
+	if s == "" {
+		return "", errors.New("yadisk: empty path")
+	}
+	root, inner := splitRoot(s)
+	switch root {
+	case RootDisk, RootApp, RootTrash:
+	default:
+		return "", fmt.Errorf("yadisk: unknown root %q of path %q", string(root), s)
+	}
+	return Path(string(root) + path.Clean("/"+inner)), nil
+}
+
+// MustParsePath is like ParsePath but panics if s is invalid.
+func MustParsePath(s string) Path {
+	p, e := ParsePath(s)
+	if e != nil {
+		panic(e)
+	}
+	return p
+}
+
+// UnescapePath parses a path with URL-escaped segments, as found in web links.
+func UnescapePath(s string) (Path, error) {
+	u, e := url.PathUnescape(s)
+	if e != nil {
+		return "", fmt.Errorf("yadisk: path %q: %v", s, e)
+	}
+	return ParsePath(u)
+}
+
+// AppFolder returns the path on the Disk of the folder of the application, the target of app:/ paths.
+func AppFolder(appName string) Path {
+	return RootDisk.Path("/Applications").Join(appName)
+}
+
+// Path returns the path p in the namespace r.
+func (r Root) Path(p string) Path {
+	return Path(string(r) + path.Clean("/"+p))
+}
+
+func (p Path) String() string {
+	return string(p)
+}
+
+// Clean returns the clean form of p, or p itself if it is invalid.
+func (p Path) Clean() Path {
+	c, e := ParsePath(string(p))
+	if e != nil {
+		return p
+	}
+	return c
+}
+
+// Root returns the namespace of p.
+func (p Path) Root() Root {
+	root, _ := splitRoot(string(p))
+	return root
+}
+
+// Inner returns p without the root, e.g. "/photos/cat.jpg".
+func (p Path) Inner() string {
+	_, inner := splitRoot(string(p.Clean()))
+	return inner
+}
+
+// IsRoot reports whether p is the root of its namespace.
+func (p Path) IsRoot() bool {
+	return p.Inner() == "/"
+}
+
+// Join appends the elements to p. Elements are cleaned and may not leave the root.
+func (p Path) Join(elem ...string) Path {
+	return p.Root().Path(path.Join(append([]string{p.Inner()}, elem...)...))
+}
+
+// Dir returns the parent folder of p. The parent of a root is the root.
+func (p Path) Dir() Path {
+	return p.Root().Path(path.Dir(p.Inner()))
+}
+
+// Base returns the last element of p, or "/" for a root.
+func (p Path) Base() string {
+	return path.Base(p.Inner())
+}
+
+// Ext returns the file name extension of p.
+func (p Path) Ext() string {
+	return path.Ext(p.Inner())
+}
+
+// Equal reports whether p and q are the same path after cleaning.
+func (p Path) Equal(q Path) bool {
+	return p.Clean() == q.Clean()
+}
+
+// Within reports whether p is parent or one of its descendants.
+func (p Path) Within(parent Path) bool {
+	_, e := parent.Rel(p)
+	return e == nil
+}
+
+// Rel returns the slash-separated path of target relative to p, "." if they are equal.
+// Target must be p or one of its descendants in the same namespace.
+func (p Path) Rel(target Path) (string, error) {
+	base, t := p.Clean(), target.Clean()
+	if base.Root() != t.Root() {
+		return "", fmt.Errorf("yadisk: %q and %q have different roots", p, target)
+	}
+	b, i := base.Inner(), t.Inner()
+	switch {
+	case b == i:
+		return ".", nil
+	case b == "/":
+		return i[1:], nil
+	case strings.HasPrefix(i, b+"/"):
+		return i[len(b)+1:], nil
+	}
+	return "", fmt.Errorf("yadisk: %q is not within %q", target, p)
+}
+
+// WithRoot returns p moved to the namespace r, keeping the inner path.
+func (p Path) WithRoot(r Root) Path {
+	return r.Path(p.Inner())
+}
+
+// ToDisk resolves an app:/ path to the path on the Disk inside the application folder, other paths are returned clean.
+func (p Path) ToDisk(appFolder Path) Path {
+	if p.Root() != RootApp {
+		return p.Clean()
+	}
+	return appFolder.Join(p.Inner())
+}
+
+// ToApp returns the app:/ form of a path inside the application folder.
+func (p Path) ToApp(appFolder Path) (Path, error) {
+	if p.Root() == RootApp {
+		return p.Clean(), nil
+	}
+	rel, e := appFolder.Rel(p)
+	if e != nil {
+		return "", e
+	}
+	return RootApp.Path(rel), nil
+}
+
+// Escape returns p with URL-escaped elements, keeping the root and the slashes.
+func (p Path) Escape() string {
+	elems := strings.Split(p.Inner(), "/")
+	for i, el := range elems {
+		elems[i] = url.PathEscape(el)
+	}
+	return string(p.Root()) + strings.Join(elems, "/")
+}
+
+// splitRoot splits s into the root and the inner path, a path without a root is on the Disk.
+func splitRoot(s string) (Root, string) {
+	if i := strings.Index(s, ":"); i > 0 && !strings.Contains(s[:i], "/") {
+		return Root(s[:i+1]), s[i+1:]
+	}
+	return RootDisk, s
+}
@@ -0,0 +1,106 @@
+package yadisk
+
+import "testing"
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Path
+		wantErr bool
+	}{
+		{"no_root", "/a/b", "disk:/a/b", false},
+		{"relative", "a/b/", "disk:/a/b", false},
+		{"disk", "disk:/a//b/./c/..", "disk:/a/b", false},
+		{"app", "app:/settings.json", "app:/settings.json", false},
+		{"trash_root", "trash:", "trash:/", false},
+		{"dot_dot", "disk:/../../a", "disk:/a", false},
+		{"colon_in_name", "/a:b/c", "disk:/a:b/c", false},
+		{"unknown_root", "ftp:/a", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	p := Path("/photos/2020/cat.jpg")
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"root", p.Root(), RootDisk},
+		{"inner", p.Inner(), "/photos/2020/cat.jpg"},
+		{"dir", p.Dir(), Path("disk:/photos/2020")},
+		{"dir_of_root", Path("app:/").Dir(), Path("app:/")},
+		{"base", p.Base(), "cat.jpg"},
+		{"ext", p.Ext(), ".jpg"},
+		{"join", Path("disk:/a").Join("b", "../c", "d.txt"), Path("disk:/a/c/d.txt")},
+		{"join_escape_root", Path("disk:/a").Join("../../etc"), Path("disk:/etc")},
+		{"equal", Path("disk:/photos/").Equal("/photos"), true},
+		{"within", p.Within("/photos"), true},
+		{"within_prefix", Path("/photos2").Within("/photos"), false},
+		{"within_other_root", Path("trash:/photos").Within("/photos"), false},
+		{"with_root", Path("disk:/a/b").WithRoot(RootTrash), Path("trash:/a/b")},
+		{"to_disk", Path("app:/cfg/a.json").ToDisk(AppFolder("My App")), Path("disk:/Applications/My App/cfg/a.json")},
+		{"to_disk_unchanged", Path("/a").ToDisk(AppFolder("x")), Path("disk:/a")},
+		{"escape", Path("disk:/my docs/50% #1?.txt").Escape(), "disk:/my%20docs/50%25%20%231%3F.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath_Rel(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    Path
+		target  Path
+		want    string
+		wantErr bool
+	}{
+		{"child", "/a", "disk:/a/b/c", "b/c", false},
+		{"same", "/a/", "disk:/a", ".", false},
+		{"from_root", "disk:/", "/a/b", "a/b", false},
+		{"sibling", "/a", "/ab", "", true},
+		{"other_root", "/a", "app:/a/b", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.base.Rel(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Path.Rel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Path.Rel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath_ToApp(t *testing.T) {
+	app := AppFolder("backup")
+	if got, err := Path("/Applications/backup/db/1.sql").ToApp(app); err != nil || got != "app:/db/1.sql" {
+		t.Errorf("Path.ToApp() = %v, error = %v", got, err)
+	}
+	if _, err := Path("/Documents/1.sql").ToApp(app); err == nil {
+		t.Errorf("Path.ToApp() error = %v, wantErr true", err)
+	}
+	if got, err := UnescapePath("disk:/my%20docs/50%25"); err != nil || got != "disk:/my docs/50%" {
+		t.Errorf("UnescapePath() = %v, error = %v", got, err)
+	}
+}
@@ -0,0 +1,97 @@
+package yadisk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	// Largest size of the JSON encoded custom properties sent in one request.
+	MaxCustomPropertiesSize = 1024
+	// Largest number of keys of the custom properties sent in one request.
+	MaxCustomPropertiesKeys = 50
+)
+
+// NewResourcePatch returns a patch setting the custom properties to the fields of props, a struct with `json` tags or a map.
+func NewResourcePatch(props interface{}) (*ResourcePatch, error) {
+	p := &ResourcePatch{CustomProperties: props}
+	if e := p.Validate(); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// Set merges a single key into the patch.
+func (p *ResourcePatch) Set(key string, value interface{}) *ResourcePatch {
+	props := p.properties()
+	props[key] = value
+	p.CustomProperties = props
+	return p
+}
+
+// Delete removes the keys from the resource, they are sent as null.
+func (p *ResourcePatch) Delete(keys ...string) *ResourcePatch {
+	props := p.properties()
+	for _, k := range keys {
+		props[k] = nil
+	}
+	p.CustomProperties = props
+	return p
+}
+
+// Validate checks the size and the number of keys of the custom properties before they are sent.
+func (p *ResourcePatch) Validate() error {
+	if p == nil || p.CustomProperties == nil {
+		return nil
+	}
+	b, e := json.Marshal(p.CustomProperties)
+	if e != nil {
+		return fmt.Errorf("yadisk: custom properties: %v", e)
+	}
+	props := map[string]json.RawMessage{}
+	if e = json.Unmarshal(b, &props); e != nil {
+		return fmt.Errorf("yadisk: custom properties must be a JSON object: %v", e)
+	}
+	if len(props) > MaxCustomPropertiesKeys {
+		return fmt.Errorf("yadisk: custom properties have %d keys, the limit is %d", len(props), MaxCustomPropertiesKeys)
+	}
+	if len(b) > MaxCustomPropertiesSize {
+		return fmt.Errorf("yadisk: custom properties take %d bytes, the limit is %d", len(b), MaxCustomPropertiesSize)
+	}
+	return nil
+}
+
+// properties returns the custom properties as a map, converting a struct through its JSON encoding.
+func (p *ResourcePatch) properties() map[string]interface{} {
+	if props, ok := p.CustomProperties.(map[string]interface{}); ok {
+		return props
+	}
+	props := map[string]interface{}{}
+	if p.CustomProperties != nil {
+		if b, e := json.Marshal(p.CustomProperties); e == nil {
+			_ = json.Unmarshal(b, &props)
+		}
+	}
+	return props
+}
+
+// DecodeCustomProperties decodes the custom properties of the resource into v.
+func (r *Resource) DecodeCustomProperties(v interface{}) error {
+	return decodeCustomProperties(r.CustomProperties, v)
+}
+
+// DecodeCustomProperties decodes the custom properties of the resource into v.
+func (r *TrashResource) DecodeCustomProperties(v interface{}) error {
+	return decodeCustomProperties(r.CustomProperties, v)
+}
+
+func decodeCustomProperties(props customProperties, v interface{}) error {
+	if props == nil {
+		return nil
+	}
+	b, e := json.Marshal(props)
+	if e != nil {
+		return e
+	}
+	return json.Unmarshal(b, v)
+}
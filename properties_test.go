@@ -0,0 +1,85 @@
+package yadisk
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testProps struct {
+	Album string   `json:"album"`
+	Tags  []string `json:"tags"`
+}
+
+func TestResourcePatch_Validate(t *testing.T) {
+	tooMany := map[string]interface{}{}
+	for i := 0; i <= MaxCustomPropertiesKeys; i++ {
+		tooMany[string(rune('a'+i%26))+strings.Repeat("x", i/26)] = 1
+	}
+	tests := []struct {
+		name    string
+		p       *ResourcePatch
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"empty", new(ResourcePatch), false},
+		{"struct", &ResourcePatch{CustomProperties: testProps{Album: "2020"}}, false},
+		{"set_delete", new(ResourcePatch).Set("a", 1).Delete("b"), false},
+		{"not_object", &ResourcePatch{CustomProperties: []string{"a"}}, true},
+		{"too_large", &ResourcePatch{CustomProperties: map[string]string{"a": strings.Repeat("x", MaxCustomPropertiesSize)}}, true},
+		{"too_many_keys", &ResourcePatch{CustomProperties: tooMany}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcePatch.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcePatch_Set(t *testing.T) {
+	p, err := NewResourcePatch(testProps{Album: "2020"})
+	if err != nil {
+		t.Fatalf("NewResourcePatch() error = %v", err)
+	}
+	b, _ := json.Marshal(p.Set("rating", 5).Delete("tags"))
+	if want := `{"custom_properties":{"album":"2020","rating":5,"tags":null}}`; string(b) != want {
+		t.Errorf("ResourcePatch = %s, want %s", b, want)
+	}
+}
+
+func TestResource_DecodeCustomProperties(t *testing.T) {
+	r := new(Resource)
+	if err := json.Unmarshal([]byte(`{"custom_properties":{"album":"2020","tags":["a","b"]}}`), r); err != nil {
+		t.Fatal(err)
+	}
+	var props testProps
+	if err := r.DecodeCustomProperties(&props); err != nil || props.Album != "2020" || len(props.Tags) != 2 {
+		t.Errorf("Resource.DecodeCustomProperties() = %+v, error = %v", props, err)
+	}
+}
+
+func Test_yandexDisk_UpdateResource_validate(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c, _ := newClient(context.Background(), &testValidToken, srv.URL, 1, nil)
+	yad := &yandexDisk{Token: &testValidToken, client: c}
+
+	large := new(ResourcePatch).Set("a", strings.Repeat("x", MaxCustomPropertiesSize))
+	if _, err := yad.UpdateResource("/a", nil, large); err == nil || body != "" {
+		t.Errorf("UpdateResource() error = %v, body = %v", err, body)
+	}
+	if _, err := yad.UpdateResource("/a", nil, new(ResourcePatch).Delete("a")); err != nil || body != `{"custom_properties":{"a":null}}` {
+		t.Errorf("UpdateResource() error = %v, body = %v", err, body)
+	}
+}
@@ -3,21 +3,44 @@ package yadisk
 import (
 	"net/http"
 	"net/url"
-	"strconv"
-	"strings"
 )
 
 // Get meta-information about a public file or directory.
 func (yad *yandexDisk) GetPublicResource(publicKey string, fields []string, limit int, offset int, path string, previewCrop bool, previewSize string, sort string) (r *PublicResource, e error) {
+	return yad.publicResource(publicKey, &PublicResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		Path:        path,
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get meta-information about a public file or directory.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) GetPublicResourceWithOptions(publicKey string, opts *PublicResourceOptions) (r *PublicResource, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.publicResource(publicKey, opts)
+}
+
+func (yad *yandexDisk) publicResource(publicKey string, opts *PublicResourceOptions) (r *PublicResource, e error) {
+	if opts == nil {
+		opts = new(PublicResourceOptions)
+	}
 	values := url.Values{}
-	values.Add("public_key", publicKey)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("limit", strconv.Itoa(limit))
-	values.Add("offset", strconv.Itoa(offset))
-	values.Add("path", path)
-	values.Add("preview_crop", strconv.FormatBool(previewCrop))
-	values.Add("preview_size", previewSize)
-	values.Add("sort", sort)
+	addValue(values, "public_key", publicKey)
+	addFields(values, opts.Fields)
+	addInt(values, "limit", opts.Limit)
+	addInt(values, "offset", opts.Offset)
+	addValue(values, "path", opts.Path)
+	addBool(values, "preview_crop", opts.PreviewCrop)
+	addValue(values, "preview_size", string(opts.PreviewSize))
+	addValue(values, "sort", string(opts.Sort))
 
 	req, e := yad.client.request(http.MethodGet, "/disk/public/resources?"+values.Encode(), nil)
 	if e != nil {
@@ -35,9 +58,9 @@ func (yad *yandexDisk) GetPublicResource(publicKey string, fields []string, limi
 // Get a link to download a public resource.
 func (yad *yandexDisk) GetPublicResourceDownloadLink(publicKey string, fields []string, path string) (l *Link, e error) {
 	values := url.Values{}
-	values.Add("public_key", publicKey)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("path", path)
+	addValue(values, "public_key", publicKey)
+	addFields(values, fields)
+	addValue(values, "path", path)
 
 	req, e := yad.client.request(http.MethodGet, "/disk/public/resources/download?"+values.Encode(), nil)
 	if e != nil {
@@ -57,13 +80,29 @@ func (yad *yandexDisk) GetPublicResourceDownloadLink(publicKey string, fields []
 // If saving occurs asynchronously, it will return a response with code 202 and a link to the asynchronous operation.
 // Otherwise, it will return a response with code 201 and a link to the created resource.
 func (yad *yandexDisk) SaveToDiskPublicResource(publicKey string, fields []string, forceAsync bool, name string, path string, savePath string) (l *Link, e error) {
+	return yad.SaveToDiskPublicResourceWithOptions(publicKey, &SaveToDiskOptions{
+		Fields:     fields,
+		ForceAsync: forceAsync,
+		Name:       name,
+		Path:       path,
+		SavePath:   savePath,
+	})
+}
+
+// Save the public resource to the Downloads folder.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) SaveToDiskPublicResourceWithOptions(publicKey string, opts *SaveToDiskOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(SaveToDiskOptions)
+	}
 	values := url.Values{}
-	values.Add("public_key", publicKey)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("force_async", strconv.FormatBool(forceAsync))
-	values.Add("name", name)
-	values.Add("path", path)
-	values.Add("save_path", savePath)
+	addValue(values, "public_key", publicKey)
+	addFields(values, opts.Fields)
+	addBool(values, "force_async", opts.ForceAsync)
+	addValue(values, "name", opts.Name)
+	addValue(values, "path", opts.Path)
+	addValue(values, "save_path", opts.SavePath)
 
 	req, e := yad.client.request(http.MethodPost, "/disk/public/resources/save-to-disk?"+values.Encode(), nil)
 	if e != nil {
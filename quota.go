@@ -0,0 +1,154 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// FreeSpace returns the bytes left on the Disk.
+func (d *Disk) FreeSpace() int64 {
+	if free := d.TotalSpace - d.UsedSpace; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// UsagePercent returns the used part of the Disk, from 0 to 100.
+func (d *Disk) UsagePercent() float64 {
+	if d.TotalSpace <= 0 {
+		return 0
+	}
+	return float64(d.UsedSpace) * 100 / float64(d.TotalSpace)
+}
+
+// QuotaError is returned by QuotaGuard for a file that cannot be uploaded.
+type QuotaError struct {
+	Path Path
+	// Size of the file, and of the file it replaces.
+	Size     int64
+	Replaced int64
+	// Maximum size of a file, from Disk.MaxFileSize.
+	MaxFileSize int64
+	// Free space of the Disk, and the part of it reserved by other uploads.
+	FreeSpace int64
+	Reserved  int64
+}
+
+func (e *QuotaError) Error() string {
+	if e.Size-e.Replaced <= e.FreeSpace-e.Reserved {
+		return fmt.Sprintf("yadisk: %s of %d bytes exceeds the maximum file size of %d bytes", e.Path, e.Size, e.MaxFileSize)
+	}
+	return fmt.Sprintf("yadisk: %s of %d bytes replacing %d bytes does not fit into %d free bytes, %d of them reserved by other uploads",
+		string(e.Path), e.Size, e.Replaced, e.FreeSpace, e.Reserved)
+}
+
+// QuotaGuard checks the space of the Disk before uploads, so files that cannot fit fail before they are sent.
+// Concurrent uploads reserve their size until they finish.
+type QuotaGuard struct {
+	yad YaDisk
+
+	mu       sync.Mutex
+	reserved int64
+}
+
+// NewQuotaGuard returns a guard of the uploads to yad.
+func NewQuotaGuard(yad YaDisk) *QuotaGuard {
+	return &QuotaGuard{yad: yad}
+}
+
+// Reserved returns the bytes reserved by the uploads in progress.
+func (g *QuotaGuard) Reserved() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.reserved
+}
+
+// Reserve reserves size bytes for the file at p, or returns a *QuotaError if it cannot fit.
+// With overwrite the space of the file replaced at p is counted as free.
+// The release function must be called once the upload finished or failed.
+func (g *QuotaGuard) Reserve(p Path, size int64, overwrite bool) (release func(), e error) {
+	var replaced int64
+	if overwrite {
+		if replaced, e = fileSize(g.yad, p); e != nil {
+			return nil, e
+		}
+	}
+	return g.reserve(p, size, size, replaced)
+}
+
+// reserve reserves the space of size bytes stored in files of at most largest bytes, which replace files of replaced bytes.
+func (g *QuotaGuard) reserve(p Path, size int64, largest int64, replaced int64) (func(), error) {
+	disk, e := g.yad.GetDisk([]string{"total_space", "used_space", "max_file_size"})
+	if e != nil {
+		return nil, e
+	}
+	need := size - replaced
+	if need < 0 {
+		need = 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if (disk.MaxFileSize > 0 && largest > disk.MaxFileSize) || need > disk.FreeSpace()-g.reserved {
+		return nil, &QuotaError{Path: p, Size: size, Replaced: replaced, MaxFileSize: disk.MaxFileSize, FreeSpace: disk.FreeSpace(), Reserved: g.reserved}
+	}
+	g.reserved += need
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			g.reserved -= need
+			g.mu.Unlock()
+		})
+	}, nil
+}
+
+// fileSize returns the size of the file at p, 0 if there is none.
+func fileSize(yad YaDisk, p Path) (int64, error) {
+	r, e := GetResourceWithOptions(yad, string(p), &ResourceOptions{Fields: []string{"type", "size"}})
+	if IsErrorID(e, ErrorIDNotFound) {
+		return 0, nil
+	}
+	if e != nil {
+		return 0, e
+	}
+	if r.Type != ResourceTypeFile {
+		return 0, nil
+	}
+	return r.Size, nil
+}
+
+// Upload uploads size bytes of r to the file at p if they fit, client is http.DefaultClient if nil.
+func (g *QuotaGuard) Upload(ctx context.Context, p Path, r io.Reader, size int64, overwrite bool, client *http.Client) error {
+	release, e := g.Reserve(p, size, overwrite)
+	if e != nil {
+		return e
+	}
+	defer release()
+	link, e := g.yad.GetResourceUploadLink(string(p), nil, overwrite)
+	if e != nil {
+		return e
+	}
+	return streamUpload(ctx, httpClient(client), link, r, size)
+}
+
+// PerformUpload uploads data to the file at p with PerformUpload of the Disk if it fits.
+func (g *QuotaGuard) PerformUpload(p Path, data *bytes.Buffer, overwrite bool) (*PerformUpload, error) {
+	var size int64
+	if data != nil {
+		size = int64(data.Len())
+	}
+	release, e := g.Reserve(p, size, overwrite)
+	if e != nil {
+		return nil, e
+	}
+	defer release()
+	link, e := g.yad.GetResourceUploadLink(string(p), nil, overwrite)
+	if e != nil {
+		return nil, e
+	}
+	return g.yad.PerformUpload(link, data)
+}
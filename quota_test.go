@@ -0,0 +1,169 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func TestDisk_FreeSpace(t *testing.T) {
+	tests := []struct {
+		name    string
+		disk    yadisk.Disk
+		free    int64
+		percent float64
+	}{
+		{"used", yadisk.Disk{TotalSpace: 1000, UsedSpace: 250}, 750, 25},
+		{"over_quota", yadisk.Disk{TotalSpace: 1000, UsedSpace: 1200}, 0, 120},
+		{"unknown", yadisk.Disk{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.disk.FreeSpace(); got != tt.free {
+				t.Errorf("Disk.FreeSpace() = %v, want %v", got, tt.free)
+			}
+			if got := tt.disk.UsagePercent(); got != tt.percent {
+				t.Errorf("Disk.UsagePercent() = %v, want %v", got, tt.percent)
+			}
+		})
+	}
+}
+
+func TestQuotaGuard_Reserve(t *testing.T) {
+	d := yadisktest.New(&yadisktest.Options{TotalSpace: 1000, MaxFileSize: 600})
+	if err := d.WriteFile("/old.bin", make([]byte, 400)); err != nil {
+		t.Fatal(err)
+	}
+	g := yadisk.NewQuotaGuard(d)
+
+	release, err := g.Reserve("/a.bin", 500, false)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	_, err = g.Reserve("/b.bin", 200, false)
+	if qe, ok := err.(*yadisk.QuotaError); !ok || qe.FreeSpace != 600 || qe.Reserved != 500 || qe.Size != 200 {
+		t.Errorf("Reserve() over the free space error = %#v", err)
+	}
+	release()
+	release()
+	if g.Reserved() != 0 {
+		t.Errorf("Reserved() = %d after release, want 0", g.Reserved())
+	}
+	if _, err := g.Reserve("/b.bin", 200, false); err != nil {
+		t.Errorf("Reserve() after release error = %v", err)
+	}
+	if _, err := g.Reserve("/c.bin", 601, false); err == nil {
+		t.Errorf("Reserve() over the maximum file size error = %v, wantErr true", err)
+	}
+	if _, err := g.Reserve("/new.bin", 550, false); err == nil {
+		t.Errorf("Reserve() of a new file over the free space error = %v, wantErr true", err)
+	}
+	if _, err := g.Reserve("/old.bin", 550, true); err != nil {
+		t.Errorf("Reserve() overwriting a file error = %v", err)
+	}
+	if g.Reserved() != 350 {
+		t.Errorf("Reserved() = %d, want 350", g.Reserved())
+	}
+}
+
+func TestQuotaGuard_Upload(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(&yadisktest.Options{TotalSpace: 1000})
+	g := yadisk.NewQuotaGuard(d)
+	if err := g.Upload(ctx, "/a.bin", bytes.NewReader(make([]byte, 700)), 700, false, d.HTTPClient()); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	err := g.Upload(ctx, "/b.bin", bytes.NewReader(make([]byte, 700)), 700, false, d.HTTPClient())
+	if _, ok := err.(*yadisk.QuotaError); !ok {
+		t.Fatalf("Upload() error = %v, want *QuotaError", err)
+	}
+	if _, err := d.GetResource("/b.bin", nil, 0, 0, false, "", ""); !yadisk.IsErrorID(err, yadisk.ErrorIDNotFound) {
+		t.Errorf("GetResource() of the refused file error = %v", err)
+	}
+}
+
+func TestCopyBetween_quota(t *testing.T) {
+	ctx := context.Background()
+	src := yadisktest.New(nil)
+	for _, p := range []string{"/src/a.bin", "/src/b.bin"} {
+		if err := src.WriteFile(p, make([]byte, 300)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dst := yadisktest.New(&yadisktest.Options{TotalSpace: 500})
+	report, err := yadisk.CopyBetween(ctx, src, "/src", dst, "/dst", &yadisk.CopyOptions{
+		Concurrency:   2,
+		SrcHTTPClient: src.HTTPClient(),
+		DstHTTPClient: dst.HTTPClient(),
+		Quota:         yadisk.NewQuotaGuard(dst),
+	})
+	if err == nil || report.Copied != 1 || len(report.Failed) != 1 {
+		t.Fatalf("CopyBetween() = %+v, error = %v", report, err)
+	}
+	if _, ok := report.Failed[0].Err.(*yadisk.QuotaError); !ok {
+		t.Errorf("CopyBetween() failure = %v, want *QuotaError", report.Failed[0].Err)
+	}
+}
+
+func TestQuotaGuard_PerformUpload(t *testing.T) {
+	d := yadisktest.New(&yadisktest.Options{TotalSpace: 1000})
+	g := yadisk.NewQuotaGuard(d)
+	if _, err := g.PerformUpload("/a.bin", bytes.NewBuffer(make([]byte, 700)), false); err != nil {
+		t.Fatalf("PerformUpload() error = %v", err)
+	}
+	_, err := g.PerformUpload("/b.bin", bytes.NewBuffer(make([]byte, 700)), false)
+	if _, ok := err.(*yadisk.QuotaError); !ok {
+		t.Fatalf("PerformUpload() error = %v, want *QuotaError", err)
+	}
+	if _, err := g.PerformUpload("/a.bin", bytes.NewBuffer(make([]byte, 900)), true); err != nil {
+		t.Errorf("PerformUpload() overwriting the file error = %v", err)
+	}
+}
+
+func TestUploadSplit_quota(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(&yadisktest.Options{TotalSpace: 4000, MaxFileSize: 1000})
+	opts := &yadisk.SplitOptions{HTTPClient: d.HTTPClient(), Quota: yadisk.NewQuotaGuard(d)}
+	if _, err := yadisk.UploadSplit(ctx, d, "/a.bin", bytes.NewReader(make([]byte, 2500)), 2500, opts); err != nil {
+		t.Fatalf("UploadSplit() error = %v", err)
+	}
+	_, err := yadisk.UploadSplit(ctx, d, "/b.bin", bytes.NewReader(make([]byte, 2500)), 2500, opts)
+	if _, ok := err.(*yadisk.QuotaError); !ok {
+		t.Fatalf("UploadSplit() error = %v, want *QuotaError", err)
+	}
+	if _, err := d.GetResource("/b.bin.part0001", nil, 0, 0, false, "", ""); !yadisk.IsErrorID(err, yadisk.ErrorIDNotFound) {
+		t.Errorf("GetResource() of a part of the refused file error = %v", err)
+	}
+	// The old file is deleted only after the new one is complete.
+	opts.Overwrite = true
+	_, err = yadisk.UploadSplit(ctx, d, "/a.bin", bytes.NewReader(make([]byte, 1500)), 1500, opts)
+	if _, ok := err.(*yadisk.QuotaError); !ok {
+		t.Fatalf("UploadSplit() overwriting the split file error = %v, want *QuotaError", err)
+	}
+	if _, err := yadisk.UploadSplit(ctx, d, "/a.bin", bytes.NewReader(make([]byte, 500)), 500, opts); err != nil {
+		t.Errorf("UploadSplit() overwriting the split file with a whole one error = %v", err)
+	}
+}
+
+func TestEncryptedDisk_quota(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(&yadisktest.Options{TotalSpace: 1000})
+	keys := &yadisk.StaticKeys{Current: "k", Keys: map[string][]byte{"k": bytes.Repeat([]byte{7}, 32)}}
+	enc, err := yadisk.NewEncryptedDisk(d, keys, &yadisk.EncryptionOptions{HTTPClient: d.HTTPClient(), Quota: yadisk.NewQuotaGuard(d)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Upload(ctx, "/a.bin", bytes.NewReader(make([]byte, 500)), 500, false); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	err = enc.Upload(ctx, "/b.bin", bytes.NewReader(make([]byte, 500)), 500, false)
+	if _, ok := err.(*yadisk.QuotaError); !ok {
+		t.Fatalf("Upload() error = %v, want *QuotaError", err)
+	}
+	if err := enc.Upload(ctx, "/a.bin", bytes.NewReader(make([]byte, 500)), 500, true); err != nil {
+		t.Errorf("Upload() overwriting the file error = %v", err)
+	}
+}
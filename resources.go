@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
-	"strconv"
-	"strings"
 )
 
 // Delete file or folder.
@@ -17,12 +15,22 @@ import (
 // If the deletion occurs asynchronously, it will return a response with status 202 and a link to the asynchronous operation.
 // Otherwise, it will return a response with status 204 and an empty body.
 func (yad *yandexDisk) DeleteResource(path string, fields []string, forceAsync bool, md5 string, permanently bool) (l *Link, e error) {
+	return yad.DeleteResourceWithOptions(path, &DeleteOptions{Fields: fields, ForceAsync: forceAsync, Md5: md5, Permanently: permanently})
+}
+
+// Delete file or folder.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) DeleteResourceWithOptions(path string, opts *DeleteOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(DeleteOptions)
+	}
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("force_async", strconv.FormatBool(forceAsync))
-	values.Add("md5", md5)
-	values.Add("permanently", strconv.FormatBool(permanently))
+	addValue(values, "path", path)
+	addFields(values, opts.Fields)
+	addBool(values, "force_async", opts.ForceAsync)
+	addValue(values, "md5", opts.Md5)
+	addBool(values, "permanently", opts.Permanently)
 
 	req, e := yad.client.request(http.MethodDelete, "/disk/resources?"+values.Encode(), nil)
 	if e != nil {
@@ -42,7 +50,28 @@ func (yad *yandexDisk) DeleteResource(path string, fields []string, forceAsync b
 
 // Get meta information about a file or directory.
 func (yad *yandexDisk) GetResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (r *Resource, e error) {
-	req, e := yad.getResource("", path, fields, limit, offset, previewCrop, previewSize, sort)
+	return yad.resource(path, &ResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get meta information about a file or directory.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) GetResourceWithOptions(path string, opts *ResourceOptions) (r *Resource, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.resource(path, opts)
+}
+
+func (yad *yandexDisk) resource(path string, opts *ResourceOptions) (r *Resource, e error) {
+	req, e := yad.getResource("", path, opts)
 	if e != nil {
 		return nil, e
 	}
@@ -56,15 +85,18 @@ func (yad *yandexDisk) GetResource(path string, fields []string, limit int, offs
 }
 
 // If the path points to a directory, the response also describes the resources of that directory.
-func (yad *yandexDisk) getResource(area string, path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*http.Request, error) {
+func (yad *yandexDisk) getResource(area string, path string, opts *ResourceOptions) (*http.Request, error) {
+	if opts == nil {
+		opts = new(ResourceOptions)
+	}
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("limit", strconv.Itoa(limit))
-	values.Add("offset", strconv.Itoa(offset))
-	values.Add("preview_crop", strconv.FormatBool(previewCrop))
-	values.Add("preview_size", previewSize)
-	values.Add("sort", sort)
+	addValue(values, "path", path)
+	addFields(values, opts.Fields)
+	addInt(values, "limit", opts.Limit)
+	addInt(values, "offset", opts.Offset)
+	addBool(values, "preview_crop", opts.PreviewCrop)
+	addValue(values, "preview_size", string(opts.PreviewSize))
+	addValue(values, "sort", string(opts.Sort))
 
 	r, e := yad.client.request(http.MethodGet, "/disk/"+area+"resources?"+values.Encode(), nil)
 	if e != nil {
@@ -76,8 +108,8 @@ func (yad *yandexDisk) getResource(area string, path string, fields []string, li
 // Create directory.
 func (yad *yandexDisk) CreateResource(path string, fields []string) (l *Link, e error) {
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
+	addValue(values, "path", path)
+	addFields(values, fields)
 
 	req, e := yad.client.request(http.MethodPut, "/disk/resources?"+values.Encode(), nil)
 	if e != nil {
@@ -95,9 +127,12 @@ func (yad *yandexDisk) CreateResource(path string, fields []string) (l *Link, e
 
 // Update User Resource Data.
 func (yad *yandexDisk) UpdateResource(path string, fields []string, body *ResourcePatch) (r *Resource, e error) {
+	if e = body.Validate(); e != nil {
+		return nil, e
+	}
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
+	addValue(values, "path", path)
+	addFields(values, fields)
 	bodyJSON, e := json.Marshal(body)
 	if e != nil {
 		return nil, e
@@ -120,7 +155,14 @@ func (yad *yandexDisk) UpdateResource(path string, fields []string, body *Resour
 // If copying occurs asynchronously, it will return a response with code 202 and a link to the asynchronous operation.
 // Otherwise, it will return a response with code 201 and a link to the created resource.
 func (yad *yandexDisk) CopyResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (l *Link, e error) {
-	return yad.transportResource("copy", from, path, fields, forceAsync, overwrite)
+	return yad.transportResource("copy", from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Create a copy of the file or folder.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) CopyResourceWithOptions(from string, path string, opts *TransferOptions) (l *Link, e error) {
+	return yad.transportResource("copy", from, path, opts)
 }
 
 // Move a file or folder.
@@ -128,16 +170,26 @@ func (yad *yandexDisk) CopyResource(from string, path string, fields []string, f
 // If the movement occurs asynchronously, it will return a response with code 202 and a link to the asynchronous operation.
 // Otherwise, it will return a response with code 201 and a link to the created resource.
 func (yad *yandexDisk) MoveResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (l *Link, e error) {
-	return yad.transportResource("move", from, path, fields, forceAsync, overwrite)
+	return yad.transportResource("move", from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Move a file or folder.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) MoveResourceWithOptions(from string, path string, opts *TransferOptions) (l *Link, e error) {
+	return yad.transportResource("move", from, path, opts)
 }
 
-func (yad *yandexDisk) transportResource(copyMove string, from string, path string, fields []string, forceAsync bool, overwrite bool) (l *Link, e error) {
+func (yad *yandexDisk) transportResource(copyMove string, from string, path string, opts *TransferOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(TransferOptions)
+	}
 	values := url.Values{}
-	values.Add("from", from)
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("force_async", strconv.FormatBool(forceAsync))
-	values.Add("overwrite", strconv.FormatBool(overwrite))
+	addValue(values, "from", from)
+	addValue(values, "path", path)
+	addFields(values, opts.Fields)
+	addBool(values, "force_async", opts.ForceAsync)
+	addBool(values, "overwrite", opts.Overwrite)
 
 	req, e := yad.client.request(http.MethodPost, "/disk/resources/"+copyMove+"?"+values.Encode(), nil)
 	if e != nil {
@@ -155,8 +207,8 @@ func (yad *yandexDisk) transportResource(copyMove string, from string, path stri
 // Get link to download file.
 func (yad *yandexDisk) GetResourceDownloadLink(path string, fields []string) (l *Link, e error) {
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
+	addValue(values, "path", path)
+	addFields(values, fields)
 
 	req, e := yad.client.request(http.MethodGet, "/disk/resources/download?"+values.Encode(), nil)
 	if e != nil {
@@ -173,14 +225,39 @@ func (yad *yandexDisk) GetResourceDownloadLink(path string, fields []string) (l
 
 // Get file list sorted by name.
 func (yad *yandexDisk) GetFlatFilesList(fields []string, limit int, mediaType string, offset int, previewCrop bool, previewSize string, sort string) (l *FilesResourceList, e error) {
+	return yad.flatFilesList(&FilesListOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		MediaType:   MediaType(mediaType),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get file list sorted by name.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) GetFlatFilesListWithOptions(opts *FilesListOptions) (l *FilesResourceList, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.flatFilesList(opts)
+}
+
+func (yad *yandexDisk) flatFilesList(opts *FilesListOptions) (l *FilesResourceList, e error) {
+	if opts == nil {
+		opts = new(FilesListOptions)
+	}
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("limit", strconv.Itoa(limit))
-	values.Add("media_type", mediaType)
-	values.Add("offset", strconv.Itoa(offset))
-	values.Add("preview_crop", strconv.FormatBool(previewCrop))
-	values.Add("preview_size", previewSize)
-	values.Add("sort", sort)
+	addFields(values, opts.Fields)
+	addInt(values, "limit", opts.Limit)
+	addValue(values, "media_type", string(opts.MediaType))
+	addInt(values, "offset", opts.Offset)
+	addBool(values, "preview_crop", opts.PreviewCrop)
+	addValue(values, "preview_size", string(opts.PreviewSize))
+	addValue(values, "sort", string(opts.Sort))
 
 	req, e := yad.client.request(http.MethodGet, "/disk/resources/files?"+values.Encode(), nil)
 	if e != nil {
@@ -197,12 +274,35 @@ func (yad *yandexDisk) GetFlatFilesList(fields []string, limit int, mediaType st
 
 // Get a list of files ordered by download date.
 func (yad *yandexDisk) GetLastUploadedFilesList(fields []string, limit int, mediaType string, previewCrop bool, previewSize string) (l *LastUploadedResourceList, e error) {
+	return yad.lastUploadedFilesList(&LastUploadedOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		MediaType:   MediaType(mediaType),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+	})
+}
+
+// Get a list of files ordered by download date.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) GetLastUploadedFilesListWithOptions(opts *LastUploadedOptions) (l *LastUploadedResourceList, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.lastUploadedFilesList(opts)
+}
+
+func (yad *yandexDisk) lastUploadedFilesList(opts *LastUploadedOptions) (l *LastUploadedResourceList, e error) {
+	if opts == nil {
+		opts = new(LastUploadedOptions)
+	}
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("limit", strconv.Itoa(limit))
-	values.Add("media_type", mediaType)
-	values.Add("preview_crop", strconv.FormatBool(previewCrop))
-	values.Add("preview_size", previewSize)
+	addFields(values, opts.Fields)
+	addInt(values, "limit", opts.Limit)
+	addValue(values, "media_type", string(opts.MediaType))
+	addBool(values, "preview_crop", opts.PreviewCrop)
+	addValue(values, "preview_size", string(opts.PreviewSize))
 
 	req, e := yad.client.request(http.MethodGet, "/disk/resources/last-uploaded?"+values.Encode(), nil)
 	if e != nil {
@@ -221,13 +321,37 @@ func (yad *yandexDisk) GetLastUploadedFilesList(fields []string, limit int, medi
 //
 // resourceType value: "","dir","file".
 func (yad *yandexDisk) ListPublicResources(fields []string, limit int, offset int, previewCrop bool, previewSize string, resourceType string) (l *PublicResourcesList, e error) {
+	return yad.publicResourcesList(&PublicResourcesListOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Type:        ResourceType(resourceType),
+	})
+}
+
+// Get a list of published resources.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) ListPublicResourcesWithOptions(opts *PublicResourcesListOptions) (l *PublicResourcesList, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.publicResourcesList(opts)
+}
+
+func (yad *yandexDisk) publicResourcesList(opts *PublicResourcesListOptions) (l *PublicResourcesList, e error) {
+	if opts == nil {
+		opts = new(PublicResourcesListOptions)
+	}
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("limit", strconv.Itoa(limit))
-	values.Add("offset", strconv.Itoa(offset))
-	values.Add("preview_crop", strconv.FormatBool(previewCrop))
-	values.Add("preview_size", previewSize)
-	values.Add("type", resourceType)
+	addFields(values, opts.Fields)
+	addInt(values, "limit", opts.Limit)
+	addInt(values, "offset", opts.Offset)
+	addBool(values, "preview_crop", opts.PreviewCrop)
+	addValue(values, "preview_size", string(opts.PreviewSize))
+	addValue(values, "type", string(opts.Type))
 
 	req, e := yad.client.request(http.MethodGet, "/disk/resources/public?"+values.Encode(), nil)
 	if e != nil {
@@ -254,8 +378,8 @@ func (yad *yandexDisk) UnpublishResource(path string, fields []string) (l *Link,
 
 func (yad *yandexDisk) pubResource(publishUnpublish string, path string, fields []string) (l *Link, e error) {
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
+	addValue(values, "path", path)
+	addFields(values, fields)
 
 	req, e := yad.client.request(http.MethodPut, "/disk/resources/"+publishUnpublish+"?"+values.Encode(), nil)
 	if e != nil {
@@ -276,11 +400,21 @@ func (yad *yandexDisk) pubResource(publishUnpublish string, path string, fields
 //
 // Therefore, in response to the request, a reference to the asynchronous operation is returned.
 func (yad *yandexDisk) UploadExternalResource(path string, externalURL string, disableRedirects bool, fields []string) (l *Link, e error) {
+	return yad.UploadExternalResourceWithOptions(path, externalURL, &UploadExternalOptions{DisableRedirects: disableRedirects, Fields: fields})
+}
+
+// Upload file to Disk by URL.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) UploadExternalResourceWithOptions(path string, externalURL string, opts *UploadExternalOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(UploadExternalOptions)
+	}
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("url", externalURL)
-	values.Add("disable_redirects", strconv.FormatBool(disableRedirects))
-	values.Add("fields", strings.Join(fields, ","))
+	addValue(values, "path", path)
+	addValue(values, "url", externalURL)
+	addBool(values, "disable_redirects", opts.DisableRedirects)
+	addFields(values, opts.Fields)
 
 	req, e := yad.client.request(http.MethodPost, "/disk/resources/upload?"+values.Encode(), nil)
 	if e != nil {
@@ -298,9 +432,9 @@ func (yad *yandexDisk) UploadExternalResource(path string, externalURL string, d
 // Get file upload link.
 func (yad *yandexDisk) GetResourceUploadLink(path string, fields []string, overwrite bool) (l *ResourceUploadLink, e error) {
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("overwrite", strconv.FormatBool(overwrite))
+	addValue(values, "path", path)
+	addFields(values, fields)
+	addBool(values, "overwrite", overwrite)
 
 	req, e := yad.client.request(http.MethodGet, "/disk/resources/upload?"+values.Encode(), nil)
 	if e != nil {
@@ -0,0 +1,168 @@
+package yadisk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RestoreConflict is the handling of a resource whose original path is taken.
+type RestoreConflict int
+
+const (
+	// Leave the resource in the Trash and report it as skipped.
+	RestoreSkip RestoreConflict = iota
+	// Replace the resource at the original path.
+	RestoreOverwrite
+	// Restore the resource next to it under a free name, e.g. "report (1).pdf".
+	RestoreRename
+)
+
+// Maximum number of names tried by RestoreRename.
+const maxRestoreNames = 100
+
+// Options of RestoreByOriginPath.
+type RestoreOptions struct {
+	// Restore only the resources deleted after this time, all of them if zero.
+	DeletedAfter time.Time
+	// Handling of taken original paths, RestoreSkip by default.
+	Conflict RestoreConflict
+	// Interval of WaitOperation, DefaultPollInterval by default.
+	PollInterval time.Duration
+}
+
+// RestoredResource is a resource brought back from the Trash.
+type RestoredResource struct {
+	// Path of the resource in the Trash before it was restored.
+	TrashPath Path
+	// Path the resource was deleted from.
+	OriginPath Path
+	// Path the resource was restored to, differs from OriginPath when it was renamed.
+	Path    Path
+	Deleted time.Time
+}
+
+// RestoreReport is the result of RestoreByOriginPath.
+type RestoreReport struct {
+	Restored []RestoredResource
+	// Resources left in the Trash, because their original path is taken
+	// or because a later deleted resource has the same original path.
+	Skipped []TrashResource
+	// Resources that failed to be restored.
+	Failed []CopyFailure
+}
+
+// RestoreByOriginPath restores the resources that were deleted from originPath, or from anywhere below it if it is a folder.
+//
+// When a path was deleted several times, the resource deleted last is restored. Folders are restored before the resources
+// deleted from inside them earlier. The error is non-nil if nothing was deleted from originPath or some resources failed.
+func RestoreByOriginPath(ctx context.Context, yad YaDisk, originPath Path, opts *RestoreOptions) (*RestoreReport, error) {
+	o := RestoreOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	origin, e := ParsePath(string(originPath))
+	if e != nil {
+		return nil, e
+	}
+
+	var candidates []TrashResource
+	for offset := 0; ; offset += walkLimit {
+		if e := ctx.Err(); e != nil {
+			return nil, e
+		}
+		trash, e := GetTrashResourceWithOptions(yad, "trash:/", &ResourceOptions{Limit: Int(walkLimit), Offset: Int(offset)})
+		if e != nil {
+			return nil, e
+		}
+		for _, item := range trash.Embedded.Items {
+			if Path(item.OriginPath).Within(origin) && (o.DeletedAfter.IsZero() || item.Deleted.After(o.DeletedAfter)) {
+				candidates = append(candidates, item)
+			}
+		}
+		if len(trash.Embedded.Items) < walkLimit || offset+walkLimit >= trash.Embedded.Total {
+			break
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("yadisk: nothing deleted from %s in the Trash", origin)
+	}
+	// Parents first, the last deleted first among resources with the same original path.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := Path(candidates[i].OriginPath).Inner(), Path(candidates[j].OriginPath).Inner()
+		if da, db := strings.Count(a, "/"), strings.Count(b, "/"); da != db {
+			return da < db
+		}
+		if a != b {
+			return a < b
+		}
+		return candidates[i].Deleted.After(candidates[j].Deleted.Time)
+	})
+
+	report := new(RestoreReport)
+	for i, item := range candidates {
+		if i > 0 && Path(candidates[i-1].OriginPath).Equal(Path(item.OriginPath)) {
+			report.Skipped = append(report.Skipped, item)
+			continue
+		}
+		restored, ok, e := restoreItem(ctx, yad, &item, &o)
+		switch {
+		case e != nil:
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			report.Failed = append(report.Failed, CopyFailure{Path: Path(item.Path), Err: e})
+		case !ok:
+			report.Skipped = append(report.Skipped, item)
+		default:
+			report.Restored = append(report.Restored, RestoredResource{
+				TrashPath:  Path(item.Path),
+				OriginPath: Path(item.OriginPath),
+				Path:       restored,
+				Deleted:    item.Deleted.Time,
+			})
+		}
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("yadisk: %d of %d resources failed to be restored, first: %v",
+			len(report.Failed), len(candidates), report.Failed[0].Err)
+	}
+	return report, nil
+}
+
+// restoreItem restores one resource and returns its new path, ok is false if it was skipped because of a conflict.
+func restoreItem(ctx context.Context, yad YaDisk, item *TrashResource, o *RestoreOptions) (p Path, ok bool, e error) {
+	if e = ctx.Err(); e != nil {
+		return "", false, e
+	}
+	l, e := yad.RestoreFromTrash(item.Path, nil, false, "", o.Conflict == RestoreOverwrite)
+	p = Path(item.OriginPath)
+	if IsErrorID(e, ErrorIDResourceAlreadyExists) && o.Conflict == RestoreRename {
+		for n := 1; n <= maxRestoreNames; n++ {
+			name := restoreName(p.Base(), n)
+			l, e = yad.RestoreFromTrash(item.Path, nil, false, name, false)
+			if !IsErrorID(e, ErrorIDResourceAlreadyExists) {
+				p = p.Dir().Join(name)
+				break
+			}
+		}
+	}
+	if IsErrorID(e, ErrorIDResourceAlreadyExists) && o.Conflict == RestoreSkip {
+		return "", false, nil
+	}
+	if e != nil {
+		return "", false, e
+	}
+	return p, true, WaitOperation(ctx, yad, l, o.PollInterval)
+}
+
+// restoreName returns the n-th alternative of a file name, e.g. "report (1).pdf".
+func restoreName(name string, n int) string {
+	ext := ""
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name, ext = name[:i], name[i:]
+	}
+	return fmt.Sprintf("%s (%d)%s", name, n, ext)
+}
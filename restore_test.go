@@ -0,0 +1,80 @@
+package yadisk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// newRestoreTrash returns a disk whose Trash holds /docs/a.txt deleted on the 1st ("v1") and on the 3rd ("v2"),
+// /docs/b.txt deleted on the 1st and the folder /docs/sub deleted on the 3rd. /docs/a.txt exists again ("v3").
+func newRestoreTrash(t *testing.T) *yadisktest.Disk {
+	now := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	d := yadisktest.New(&yadisktest.Options{Now: func() time.Time { return now }})
+	write := func(p string, data string) {
+		if err := d.WriteFile(p, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	remove := func(p yadisk.Path) {
+		if _, err := d.DeleteResource(string(p), nil, false, "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("/docs/a.txt", "v1")
+	write("/docs/b.txt", "b")
+	write("/docs/sub/c.txt", "c")
+	remove("/docs/a.txt")
+	remove("/docs/b.txt")
+	now = now.AddDate(0, 0, 2)
+	write("/docs/a.txt", "v2")
+	remove("/docs/a.txt")
+	remove("/docs/sub")
+	write("/docs/a.txt", "v3")
+	return d
+}
+
+func TestRestoreByOriginPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   yadisk.Path
+		opts     yadisk.RestoreOptions
+		restored map[yadisk.Path]string
+		skipped  int
+		wantErr  bool
+	}{
+		{"skip", "/docs", yadisk.RestoreOptions{}, map[yadisk.Path]string{"/docs/b.txt": "b", "/docs/sub/c.txt": "c", "/docs/a.txt": "v3"}, 2, false},
+		{"overwrite", "/docs/a.txt", yadisk.RestoreOptions{Conflict: yadisk.RestoreOverwrite}, map[yadisk.Path]string{"/docs/a.txt": "v2"}, 1, false},
+		{"rename", "disk:/docs/a.txt", yadisk.RestoreOptions{Conflict: yadisk.RestoreRename}, map[yadisk.Path]string{"/docs/a.txt": "v3", "/docs/a (1).txt": "v2"}, 1, false},
+		{"deleted_after", "/docs", yadisk.RestoreOptions{DeletedAfter: time.Date(2020, 5, 2, 0, 0, 0, 0, time.UTC)}, map[yadisk.Path]string{"/docs/sub/c.txt": "c"}, 1, false},
+		{"not_found", "/photos", yadisk.RestoreOptions{}, nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newRestoreTrash(t)
+			report, err := yadisk.RestoreByOriginPath(context.Background(), d, tt.origin, &tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RestoreByOriginPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(report.Skipped) != tt.skipped || len(report.Failed) != 0 {
+				t.Errorf("RestoreByOriginPath() = %+v, want %d skipped", report, tt.skipped)
+			}
+			for _, r := range report.Restored {
+				if r.OriginPath.Inner() == "/docs/a.txt" && r.Deleted.Day() != 3 {
+					t.Errorf("RestoreByOriginPath() restored %+v, want the last deleted", r)
+				}
+			}
+			for p, want := range tt.restored {
+				if got := readFile(t, d, p); got != want {
+					t.Errorf("%s = %q, want %q", p, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// TrashPolicy selects the resources in the Trash to remove. All the set criteria must match.
+type TrashPolicy struct {
+	// Deleted at least this long ago.
+	OlderThan time.Duration
+	// At least this many bytes, the contents of a folder are summed up.
+	MinSize int64
+	// Pattern of path.Match for the origin path, e.g. "/ci/*/artifacts". Patterns without a root match paths on the Disk.
+	OriginGlob string
+	// One of the media types, files only.
+	MediaTypes []MediaType
+	// Clock of OlderThan, time.Now by default.
+	Now func() time.Time
+}
+
+// Options of ApplyTrashPolicy.
+type RetentionOptions struct {
+	// Only report the resources that would be removed.
+	DryRun bool
+	// Interval of WaitOperation, DefaultPollInterval by default.
+	PollInterval time.Duration
+	// Receives a line per selected resource, if set.
+	Output io.Writer
+}
+
+// RetentionReport is the result of ApplyTrashPolicy.
+type RetentionReport struct {
+	// Resources selected by the policy, with the size of folders summed up.
+	Selected []TrashResource
+	// Resources removed, zero on a dry run.
+	Removed int
+	// Bytes freed in the Trash, or the bytes that would be freed on a dry run.
+	ReclaimedBytes int64
+	// Resources that failed to be removed.
+	Failed []CopyFailure
+}
+
+// Validate returns an error for a policy without criteria, which would empty the whole Trash, or with an invalid pattern.
+func (p *TrashPolicy) Validate() error {
+	if p == nil || (p.OlderThan <= 0 && p.MinSize <= 0 && p.OriginGlob == "" && len(p.MediaTypes) == 0) {
+		return errors.New("yadisk: trash policy without criteria")
+	}
+	if _, e := path.Match(p.OriginGlob, ""); e != nil {
+		return fmt.Errorf("yadisk: origin glob %q: %v", p.OriginGlob, e)
+	}
+	return validate(MediaTypes(p.MediaTypes...).Validate())
+}
+
+// match reports whether the resource matches the criteria other than the size.
+func (p *TrashPolicy) match(r *TrashResource) bool {
+	if p.OlderThan > 0 {
+		now := time.Now
+		if p.Now != nil {
+			now = p.Now
+		}
+		if r.Deleted.IsZero() || now().Sub(r.Deleted.Time) < p.OlderThan {
+			return false
+		}
+	}
+	if p.OriginGlob != "" {
+		origin := Path(r.OriginPath).Clean()
+		name := origin.Inner()
+		if strings.Contains(p.OriginGlob, ":") {
+			name = string(origin)
+		}
+		if ok, _ := path.Match(p.OriginGlob, name); !ok {
+			return false
+		}
+	}
+	if len(p.MediaTypes) > 0 {
+		if r.Type == ResourceTypeDir {
+			return false
+		}
+		found := false
+		for _, m := range p.MediaTypes {
+			found = found || m == r.MediaType
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyTrashPolicy removes the resources in the Trash selected by the policy, one by one, waiting for each removal.
+func ApplyTrashPolicy(ctx context.Context, yad YaDisk, policy *TrashPolicy, opts *RetentionOptions) (*RetentionReport, error) {
+	if e := policy.Validate(); e != nil {
+		return nil, e
+	}
+	o := RetentionOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	// The listing is read completely before removing, as removals shift the pages.
+	var candidates []TrashResource
+	for offset := 0; ; offset += walkLimit {
+		if e := ctx.Err(); e != nil {
+			return nil, e
+		}
+		trash, e := GetTrashResourceWithOptions(yad, "trash:/", &ResourceOptions{Limit: Int(walkLimit), Offset: Int(offset), Sort: SortDeleted})
+		if e != nil {
+			return nil, e
+		}
+		for _, item := range trash.Embedded.Items {
+			if policy.match(&item) {
+				candidates = append(candidates, item)
+			}
+		}
+		if len(trash.Embedded.Items) < walkLimit || offset+walkLimit >= trash.Embedded.Total {
+			break
+		}
+	}
+
+	report := new(RetentionReport)
+	for _, item := range candidates {
+		if item.Type == ResourceTypeDir {
+			size, e := trashFolderSize(ctx, yad, item.Path)
+			if e != nil {
+				return report, e
+			}
+			item.Size = size
+		}
+		if item.Size < policy.MinSize {
+			continue
+		}
+		report.Selected = append(report.Selected, item)
+		if o.DryRun {
+			report.ReclaimedBytes += item.Size
+			printRetention(o.Output, "would remove", &item)
+			continue
+		}
+		if e := clearTrashItem(ctx, yad, item.Path, o.PollInterval); e != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			report.Failed = append(report.Failed, CopyFailure{Path: Path(item.Path), Err: e})
+			printRetention(o.Output, "failed to remove", &item)
+			continue
+		}
+		report.Removed++
+		report.ReclaimedBytes += item.Size
+		printRetention(o.Output, "removed", &item)
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("yadisk: %d of %d resources failed to be removed from the Trash, first: %v",
+			len(report.Failed), len(report.Selected), report.Failed[0].Err)
+	}
+	return report, nil
+}
+
+func clearTrashItem(ctx context.Context, yad YaDisk, p string, interval time.Duration) error {
+	l, e := yad.ClearTrash(nil, false, p)
+	if e != nil {
+		return e
+	}
+	return WaitOperation(ctx, yad, l, interval)
+}
+
+// trashFolderSize sums up the sizes of the files in a folder in the Trash.
+func trashFolderSize(ctx context.Context, yad YaDisk, dir string) (int64, error) {
+	var size int64
+	queue := []string{dir}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for offset := 0; ; offset += walkLimit {
+			if e := ctx.Err(); e != nil {
+				return 0, e
+			}
+			r, e := GetTrashResourceWithOptions(yad, p, &ResourceOptions{Fields: embeddedFields("path", "type", "size"), Limit: Int(walkLimit), Offset: Int(offset)})
+			if e != nil {
+				return 0, e
+			}
+			for _, item := range r.Embedded.Items {
+				if item.Type == ResourceTypeDir {
+					queue = append(queue, item.Path)
+				} else {
+					size += item.Size
+				}
+			}
+			if len(r.Embedded.Items) < walkLimit || offset+walkLimit >= r.Embedded.Total {
+				break
+			}
+		}
+	}
+	return size, nil
+}
+
+func printRetention(w io.Writer, action string, r *TrashResource) {
+	if w == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s %s (from %s, deleted %s, %d bytes)\n",
+		action, r.Path, r.OriginPath, r.Deleted.Format(time.RFC3339), r.Size)
+}
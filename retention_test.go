@@ -0,0 +1,100 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// newTrash returns a disk whose Trash holds /ci/build-1 (deleted 10 days ago, 300 bytes), /ci/build-2 (2 days, 200 bytes),
+// /photo.jpg (10 days, 50 bytes) and /notes.txt (10 days, 5 bytes).
+func newTrash(t *testing.T) (*yadisktest.Disk, func() time.Time) {
+	now := time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	d := yadisktest.New(&yadisktest.Options{Now: clock})
+	files := map[string]int{
+		"/ci/build-1/a.bin": 100, "/ci/build-1/sub/b.bin": 200,
+		"/ci/build-2/a.bin": 200,
+		"/photo.jpg":        50,
+		"/notes.txt":        5,
+	}
+	for p, size := range files {
+		if err := d.WriteFile(p, bytes.Repeat([]byte{1}, size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	remove := func(p yadisk.Path, daysAgo int) {
+		now = time.Date(2020, 5, 20-daysAgo, 0, 0, 0, 0, time.UTC)
+		if _, err := d.DeleteResource(string(p), nil, false, "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	remove("/ci/build-1", 10)
+	remove("/ci/build-2", 2)
+	remove("/photo.jpg", 10)
+	remove("/notes.txt", 10)
+	now = time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+	return d, clock
+}
+
+func TestApplyTrashPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    yadisk.TrashPolicy
+		selected  []string
+		reclaimed int64
+		wantErr   bool
+	}{
+		{"age", yadisk.TrashPolicy{OlderThan: 7 * 24 * time.Hour}, []string{"/ci/build-1", "/notes.txt", "/photo.jpg"}, 355, false},
+		{"glob", yadisk.TrashPolicy{OriginGlob: "/ci/*"}, []string{"/ci/build-1", "/ci/build-2"}, 500, false},
+		{"glob_and_age", yadisk.TrashPolicy{OriginGlob: "disk:/ci/*", OlderThan: 7 * 24 * time.Hour}, []string{"/ci/build-1"}, 300, false},
+		{"size", yadisk.TrashPolicy{MinSize: 100}, []string{"/ci/build-1", "/ci/build-2"}, 500, false},
+		{"media_type", yadisk.TrashPolicy{MediaTypes: []yadisk.MediaType{yadisk.MediaTypeImage}}, []string{"/photo.jpg"}, 50, false},
+		{"empty", yadisk.TrashPolicy{}, nil, 0, true},
+		{"bad_glob", yadisk.TrashPolicy{OriginGlob: "["}, nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d, clock := newTrash(t)
+			tt.policy.Now = clock
+			out := new(bytes.Buffer)
+			opts := &yadisk.RetentionOptions{DryRun: true, Output: out, PollInterval: time.Millisecond}
+
+			dry, err := yadisk.ApplyTrashPolicy(ctx, d, &tt.policy, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyTrashPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var selected []string
+			for _, r := range dry.Selected {
+				selected = append(selected, yadisk.Path(r.OriginPath).Inner())
+			}
+			sort.Strings(selected)
+			if strings.Join(selected, ",") != strings.Join(tt.selected, ",") {
+				t.Errorf("ApplyTrashPolicy() selected = %v, want %v", selected, tt.selected)
+			}
+			if dry.ReclaimedBytes != tt.reclaimed || dry.Removed != 0 || strings.Count(out.String(), "would remove") != len(tt.selected) {
+				t.Errorf("ApplyTrashPolicy() dry run = %+v, output %q", dry, out.String())
+			}
+
+			opts.DryRun = false
+			report, err := yadisk.ApplyTrashPolicy(ctx, d, &tt.policy, opts)
+			if err != nil || report.Removed != len(tt.selected) || report.ReclaimedBytes != tt.reclaimed {
+				t.Fatalf("ApplyTrashPolicy() = %+v, error = %v", report, err)
+			}
+			trash, _ := d.GetTrashResource("", nil, 0, 0, false, "", "")
+			if len(trash.Embedded.Items) != 4-len(tt.selected) {
+				t.Errorf("Trash items = %d, want %d", len(trash.Embedded.Items), 4-len(tt.selected))
+			}
+		})
+	}
+}
@@ -0,0 +1,569 @@
+package yadisk
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// ScopeError is returned by ScopedDisk for a path outside of its scope.
+type ScopeError struct {
+	Path  Path
+	Scope Path
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("yadisk: %q is outside of the scope %q", e.Path, e.Scope)
+}
+
+// ScopedDisk is a YaDisk restricted to one folder, e.g. "app:/" for applications with access to their folder only.
+//
+// Paths are relative to the folder: "/a.txt" and "disk:/a.txt" refer to <scope>/a.txt, and the paths in responses
+// are rewritten the same way. Listings of the whole Disk and the Trash are filtered to the resources of the scope,
+// so they may return fewer items than the limit.
+type ScopedDisk struct {
+	yad   YaDisk
+	scope Path
+
+	mu sync.Mutex
+	// The scope as a disk:/ path, the form of the paths in responses to app:/ requests.
+	diskScope Path
+}
+
+var _ YaDiskWithOptions = (*ScopedDisk)(nil)
+
+// NewScopedDisk returns yad restricted to the folder scope, on the Disk or in the application folder.
+func NewScopedDisk(yad YaDisk, scope Path) (*ScopedDisk, error) {
+	s, e := ParsePath(string(scope))
+	if e != nil {
+		return nil, e
+	}
+	if s.Root() == RootTrash {
+		return nil, fmt.Errorf("yadisk: scope %q is in the Trash", scope)
+	}
+	return &ScopedDisk{yad: yad, scope: s}, nil
+}
+
+// Scope returns the folder the disk is restricted to.
+func (s *ScopedDisk) Scope() Path {
+	return s.scope
+}
+
+// in returns the path of the underlying disk for a path of the scope.
+func (s *ScopedDisk) in(p string) (string, error) {
+	if p == "" {
+		return string(s.scope), nil
+	}
+	c, e := ParsePath(p)
+	if e != nil {
+		return "", e
+	}
+	if c.Root() != RootDisk {
+		return "", &ScopeError{Path: Path(p), Scope: s.scope}
+	}
+	return string(s.scope.Join(c.Inner())), nil
+}
+
+// out returns the path of the scope for a path of the underlying disk.
+func (s *ScopedDisk) out(p string) (string, error) {
+	if p == "" {
+		return "", nil
+	}
+	if rel, e := s.scope.Rel(Path(p)); e == nil {
+		return string(RootDisk.Path(rel)), nil
+	}
+	if s.scope.Root() == RootApp && Path(p).Root() == RootDisk {
+		diskScope, e := s.discover()
+		if e != nil {
+			return "", e
+		}
+		if rel, e := diskScope.Rel(Path(p)); e == nil {
+			return string(RootDisk.Path(rel)), nil
+		}
+	}
+	return "", &ScopeError{Path: Path(p), Scope: s.scope}
+}
+
+// discover asks the API for the disk:/ path of an app:/ scope.
+func (s *ScopedDisk) discover() (Path, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.diskScope != "" {
+		return s.diskScope, nil
+	}
+	r, e := GetResourceWithOptions(s.yad, string(s.scope), &ResourceOptions{Fields: []string{"path"}, Limit: Int(1)})
+	if e != nil {
+		return "", e
+	}
+	if Path(r.Path).Root() != RootDisk {
+		return "", fmt.Errorf("yadisk: unexpected path %q of the scope %q", r.Path, s.scope)
+	}
+	s.diskScope = Path(r.Path).Clean()
+	return s.diskScope, nil
+}
+
+func (s *ScopedDisk) within(p string) bool {
+	_, e := s.out(p)
+	return e == nil
+}
+
+func (s *ScopedDisk) rewrite(r *baseResource) (e error) {
+	r.Path, e = s.out(r.Path)
+	return
+}
+
+func (s *ScopedDisk) rewriteResource(r *Resource) error {
+	if e := s.rewrite(&r.baseResource); e != nil {
+		return e
+	}
+	var e error
+	if r.Embedded.Path, e = s.out(r.Embedded.Path); e != nil {
+		return e
+	}
+	for i := range r.Embedded.Items {
+		if e := s.rewriteResource(&r.Embedded.Items[i]); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// filter keeps the items of a listing inside the scope and rewrites their paths.
+func (s *ScopedDisk) filter(items []Resource) ([]Resource, error) {
+	kept := items[:0]
+	for _, item := range items {
+		if item.Path != "" && !s.within(item.Path) {
+			continue
+		}
+		if e := s.rewriteResource(&item); e != nil {
+			return nil, e
+		}
+		kept = append(kept, item)
+	}
+	return kept, nil
+}
+
+// trashItem checks that the resource in the Trash was deleted from the scope.
+func (s *ScopedDisk) trashItem(path string) error {
+	if path == "" || MustParsePath("trash:/").Equal(Path(path)) {
+		return &ScopeError{Path: "trash:/", Scope: s.scope}
+	}
+	r, e := GetTrashResourceWithOptions(s.yad, path, &ResourceOptions{Fields: []string{"origin_path"}, Limit: Int(1)})
+	if e != nil {
+		return e
+	}
+	if !s.within(r.OriginPath) {
+		return &ScopeError{Path: Path(r.OriginPath), Scope: s.scope}
+	}
+	return nil
+}
+
+// withField adds a field needed to check the scope to a non-empty projection.
+func withField(fields []string, field string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	for _, f := range fields {
+		if f == field {
+			return fields
+		}
+	}
+	return append(append([]string(nil), fields...), field)
+}
+
+// Get user disk meta information.
+func (s *ScopedDisk) GetDisk(fields []string) (*Disk, error) {
+	return s.yad.GetDisk(fields)
+}
+
+// Empty trash.
+//
+// Only a resource deleted from the scope can be removed, the whole Trash cannot be emptied.
+func (s *ScopedDisk) ClearTrash(fields []string, forceAsync bool, path string) (*Link, error) {
+	return s.ClearTrashWithOptions(&ClearTrashOptions{Fields: fields, ForceAsync: forceAsync, Path: path})
+}
+
+// Empty trash.
+//
+// Only a resource deleted from the scope can be removed, the whole Trash cannot be emptied.
+func (s *ScopedDisk) ClearTrashWithOptions(opts *ClearTrashOptions) (*Link, error) {
+	path := ""
+	if opts != nil {
+		path = opts.Path
+	}
+	if e := s.trashItem(path); e != nil {
+		return nil, e
+	}
+	return ClearTrashWithOptions(s.yad, opts)
+}
+
+// Get the contents of the Trash.
+func (s *ScopedDisk) GetTrashResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*TrashResource, error) {
+	return s.GetTrashResourceWithOptions(path, &ResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get the contents of the Trash.
+//
+// Only the resources deleted from the scope are listed, their origin paths are relative to the scope.
+func (s *ScopedDisk) GetTrashResourceWithOptions(path string, opts *ResourceOptions) (*TrashResource, error) {
+	if opts != nil && len(opts.Fields) > 0 {
+		o := *opts
+		o.Fields = withField(withField(o.Fields, "origin_path"), "_embedded.items.origin_path")
+		opts = &o
+	}
+	isRoot := path == "" || MustParsePath("trash:/").Equal(Path(path))
+	if !isRoot {
+		if e := s.trashItem(path); e != nil {
+			return nil, e
+		}
+	}
+	r, e := GetTrashResourceWithOptions(s.yad, path, opts)
+	if e != nil {
+		return nil, e
+	}
+	if !isRoot {
+		r.OriginPath, e = s.out(r.OriginPath)
+		return r, e
+	}
+	kept := r.Embedded.Items[:0]
+	for _, item := range r.Embedded.Items {
+		if !s.within(item.OriginPath) {
+			continue
+		}
+		if item.OriginPath, e = s.out(item.OriginPath); e != nil {
+			return nil, e
+		}
+		kept = append(kept, item)
+	}
+	r.Embedded.Items = kept
+	return r, nil
+}
+
+// Recover Resource from Trash.
+//
+// Only a resource deleted from the scope can be restored.
+func (s *ScopedDisk) RestoreFromTrash(path string, fields []string, forceAsync bool, name string, overwrite bool) (*Link, error) {
+	return s.RestoreFromTrashWithOptions(path, &RestoreFromTrashOptions{Fields: fields, ForceAsync: forceAsync, Name: name, Overwrite: overwrite})
+}
+
+// Recover Resource from Trash.
+//
+// Only a resource deleted from the scope can be restored.
+func (s *ScopedDisk) RestoreFromTrashWithOptions(path string, opts *RestoreFromTrashOptions) (*Link, error) {
+	if e := s.trashItem(path); e != nil {
+		return nil, e
+	}
+	return RestoreFromTrashWithOptions(s.yad, path, opts)
+}
+
+// Delete file or folder.
+func (s *ScopedDisk) DeleteResource(path string, fields []string, forceAsync bool, md5 string, permanently bool) (*Link, error) {
+	return s.DeleteResourceWithOptions(path, &DeleteOptions{Fields: fields, ForceAsync: forceAsync, Md5: md5, Permanently: permanently})
+}
+
+// Delete file or folder.
+func (s *ScopedDisk) DeleteResourceWithOptions(path string, opts *DeleteOptions) (*Link, error) {
+	p, e := s.target(path)
+	if e != nil {
+		return nil, e
+	}
+	return DeleteResourceWithOptions(s.yad, p, opts)
+}
+
+// Get meta information about a file or directory.
+func (s *ScopedDisk) GetResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*Resource, error) {
+	return s.GetResourceWithOptions(path, &ResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get meta information about a file or directory.
+func (s *ScopedDisk) GetResourceWithOptions(path string, opts *ResourceOptions) (*Resource, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	r, e := GetResourceWithOptions(s.yad, p, opts)
+	if e != nil {
+		return nil, e
+	}
+	return r, s.rewriteResource(r)
+}
+
+// Create directory.
+func (s *ScopedDisk) CreateResource(path string, fields []string) (*Link, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	return s.yad.CreateResource(p, fields)
+}
+
+// Update User Resource Data.
+func (s *ScopedDisk) UpdateResource(path string, fields []string, body *ResourcePatch) (*Resource, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	r, e := s.yad.UpdateResource(p, fields, body)
+	if e != nil {
+		return nil, e
+	}
+	return r, s.rewriteResource(r)
+}
+
+// Create a copy of the file or folder. Both paths are inside the scope.
+func (s *ScopedDisk) CopyResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*Link, error) {
+	return s.CopyResourceWithOptions(from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Create a copy of the file or folder. Both paths are inside the scope.
+func (s *ScopedDisk) CopyResourceWithOptions(from string, path string, opts *TransferOptions) (*Link, error) {
+	f, p, e := s.transferPaths(from, path)
+	if e != nil {
+		return nil, e
+	}
+	return CopyResourceWithOptions(s.yad, f, p, opts)
+}
+
+// Move a file or folder. Both paths are inside the scope.
+func (s *ScopedDisk) MoveResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*Link, error) {
+	return s.MoveResourceWithOptions(from, path, &TransferOptions{Fields: fields, ForceAsync: forceAsync, Overwrite: overwrite})
+}
+
+// Move a file or folder. Both paths are inside the scope.
+func (s *ScopedDisk) MoveResourceWithOptions(from string, path string, opts *TransferOptions) (*Link, error) {
+	f, p, e := s.transferPaths(from, path)
+	if e != nil {
+		return nil, e
+	}
+	return MoveResourceWithOptions(s.yad, f, p, opts)
+}
+
+func (s *ScopedDisk) transferPaths(from string, path string) (string, string, error) {
+	f, e := s.target(from)
+	if e != nil {
+		return "", "", e
+	}
+	p, e := s.target(path)
+	if e != nil {
+		return "", "", e
+	}
+	return f, p, nil
+}
+
+// target returns the path on the Disk of a resource to delete, move or replace, which cannot be the scope itself.
+func (s *ScopedDisk) target(path string) (string, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return "", e
+	}
+	if s.scope.Equal(Path(p)) {
+		return "", &ScopeError{Path: Path(path), Scope: s.scope}
+	}
+	return p, nil
+}
+
+// Get link to download file.
+func (s *ScopedDisk) GetResourceDownloadLink(path string, fields []string) (*Link, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	return s.yad.GetResourceDownloadLink(p, fields)
+}
+
+// Get file list sorted by name.
+func (s *ScopedDisk) GetFlatFilesList(fields []string, limit int, mediaType string, offset int, previewCrop bool, previewSize string, sort string) (*FilesResourceList, error) {
+	return s.GetFlatFilesListWithOptions(&FilesListOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		MediaType:   MediaType(mediaType),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get file list sorted by name.
+//
+// Only the files of the scope are returned, the limit and the offset apply to the whole Disk.
+func (s *ScopedDisk) GetFlatFilesListWithOptions(opts *FilesListOptions) (*FilesResourceList, error) {
+	if opts != nil {
+		o := *opts
+		o.Fields = withField(o.Fields, "items.path")
+		opts = &o
+	}
+	l, e := GetFlatFilesListWithOptions(s.yad, opts)
+	if e != nil {
+		return nil, e
+	}
+	l.Items, e = s.filter(l.Items)
+	return l, e
+}
+
+// Get a list of files ordered by download date.
+func (s *ScopedDisk) GetLastUploadedFilesList(fields []string, limit int, mediaType string, previewCrop bool, previewSize string) (*LastUploadedResourceList, error) {
+	return s.GetLastUploadedFilesListWithOptions(&LastUploadedOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		MediaType:   MediaType(mediaType),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+	})
+}
+
+// Get a list of files ordered by download date.
+//
+// Only the files of the scope are returned, the limit applies to the whole Disk.
+func (s *ScopedDisk) GetLastUploadedFilesListWithOptions(opts *LastUploadedOptions) (*LastUploadedResourceList, error) {
+	if opts != nil {
+		o := *opts
+		o.Fields = withField(o.Fields, "items.path")
+		opts = &o
+	}
+	l, e := GetLastUploadedFilesListWithOptions(s.yad, opts)
+	if e != nil {
+		return nil, e
+	}
+	l.Items, e = s.filter(l.Items)
+	return l, e
+}
+
+// Get a list of published resources.
+func (s *ScopedDisk) ListPublicResources(fields []string, limit int, offset int, previewCrop bool, previewSize string, resourceType string) (*PublicResourcesList, error) {
+	return s.ListPublicResourcesWithOptions(&PublicResourcesListOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Type:        ResourceType(resourceType),
+	})
+}
+
+// Get a list of published resources.
+//
+// Only the resources of the scope are returned, the limit and the offset apply to the whole Disk.
+func (s *ScopedDisk) ListPublicResourcesWithOptions(opts *PublicResourcesListOptions) (*PublicResourcesList, error) {
+	if opts != nil {
+		o := *opts
+		o.Fields = withField(o.Fields, "items.path")
+		opts = &o
+	}
+	l, e := ListPublicResourcesWithOptions(s.yad, opts)
+	if e != nil {
+		return nil, e
+	}
+	l.Items, e = s.filter(l.Items)
+	return l, e
+}
+
+// Publish a resource.
+func (s *ScopedDisk) PublishResource(path string, fields []string) (*Link, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	return s.yad.PublishResource(p, fields)
+}
+
+// Unpublish a resource.
+func (s *ScopedDisk) UnpublishResource(path string, fields []string) (*Link, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	return s.yad.UnpublishResource(p, fields)
+}
+
+// Upload file to Disk by URL.
+func (s *ScopedDisk) UploadExternalResource(path string, externalURL string, disableRedirects bool, fields []string) (*Link, error) {
+	return s.UploadExternalResourceWithOptions(path, externalURL, &UploadExternalOptions{DisableRedirects: disableRedirects, Fields: fields})
+}
+
+// Upload file to Disk by URL.
+func (s *ScopedDisk) UploadExternalResourceWithOptions(path string, externalURL string, opts *UploadExternalOptions) (*Link, error) {
+	p, e := s.in(path)
+	if e != nil {
+		return nil, e
+	}
+	return UploadExternalResourceWithOptions(s.yad, p, externalURL, opts)
+}
+
+// Get file download link.
+func (s *ScopedDisk) GetResourceUploadLink(path string, fields []string, overwrite bool) (*ResourceUploadLink, error) {
+	p, e := s.target(path)
+	if e != nil {
+		return nil, e
+	}
+	return s.yad.GetResourceUploadLink(p, fields, overwrite)
+}
+
+// Get meta-information about a public file or directory.
+func (s *ScopedDisk) GetPublicResource(publicKey string, fields []string, limit int, offset int, path string, previewCrop bool, previewSize string, sort string) (*PublicResource, error) {
+	return s.yad.GetPublicResource(publicKey, fields, limit, offset, path, previewCrop, previewSize, sort)
+}
+
+// Get meta-information about a public file or directory.
+func (s *ScopedDisk) GetPublicResourceWithOptions(publicKey string, opts *PublicResourceOptions) (*PublicResource, error) {
+	return GetPublicResourceWithOptions(s.yad, publicKey, opts)
+}
+
+// Get a link to download a public resource.
+func (s *ScopedDisk) GetPublicResourceDownloadLink(publicKey string, fields []string, path string) (*Link, error) {
+	return s.yad.GetPublicResourceDownloadLink(publicKey, fields, path)
+}
+
+// Save the public resource to a folder of the scope, its root if savePath is empty.
+func (s *ScopedDisk) SaveToDiskPublicResource(publicKey string, fields []string, forceAsync bool, name string, path string, savePath string) (*Link, error) {
+	return s.SaveToDiskPublicResourceWithOptions(publicKey, &SaveToDiskOptions{
+		Fields:     fields,
+		ForceAsync: forceAsync,
+		Name:       name,
+		Path:       path,
+		SavePath:   savePath,
+	})
+}
+
+// Save the public resource to a folder of the scope, its root if SavePath is empty.
+func (s *ScopedDisk) SaveToDiskPublicResourceWithOptions(publicKey string, opts *SaveToDiskOptions) (*Link, error) {
+	o := SaveToDiskOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	p, e := s.in(o.SavePath)
+	if e != nil {
+		return nil, e
+	}
+	o.SavePath = p
+	return SaveToDiskPublicResourceWithOptions(s.yad, publicKey, &o)
+}
+
+// Get the status of an asynchronous operation.
+func (s *ScopedDisk) GetOperationStatus(operationID string, fields []string) (*OperationStatus, error) {
+	return s.yad.GetOperationStatus(operationID, fields)
+}
+
+// This custom method to upload data by link.
+func (s *ScopedDisk) PerformUpload(ur *ResourceUploadLink, data *bytes.Buffer) (*PerformUpload, error) {
+	return s.yad.PerformUpload(ur, data)
+}
+
+// This custom method to upload data by link.
+func (s *ScopedDisk) PerformPartialUpload(ur *ResourceUploadLink, data *bytes.Buffer, partSize int64) (*PerformUpload, error) {
+	return s.yad.PerformPartialUpload(ur, data, partSize)
+}
@@ -0,0 +1,118 @@
+package yadisk_test
+
+import (
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func newScopedDisk(t *testing.T, scope yadisk.Path) (*yadisktest.Disk, *yadisk.ScopedDisk) {
+	d := yadisktest.New(&yadisktest.Options{AppName: "test"})
+	for _, p := range []string{"/Applications/test/a.txt", "/Applications/test/dir/b.txt", "/secret.txt", "/work/c.txt"} {
+		if err := d.WriteFile(p, []byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := yadisk.NewScopedDisk(d, scope)
+	if err != nil {
+		t.Fatalf("NewScopedDisk() error = %v", err)
+	}
+	return d, s
+}
+
+func TestScopedDisk_paths(t *testing.T) {
+	_, s := newScopedDisk(t, "app:/")
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr bool
+	}{
+		{"get", func() error { _, e := s.GetResource("/a.txt", nil, 0, 0, false, "", ""); return e }, false},
+		{"escape_dot_dot", func() error { _, e := s.GetResource("/../../secret.txt", nil, 0, 0, false, "", ""); return e }, true},
+		{"other_root", func() error { _, e := s.GetResource("trash:/secret.txt", nil, 0, 0, false, "", ""); return e }, true},
+		{"copy_from_outside", func() error { _, e := s.CopyResource("app:/../secret.txt", "/x.txt", nil, false, false); return e }, true},
+		{"move_from_trash", func() error { _, e := s.MoveResource("trash:/secret.txt", "/x.txt", nil, false, false); return e }, true},
+		{"copy_inside", func() error { _, e := s.CopyResource("/a.txt", "/dir/a.txt", nil, false, false); return e }, false},
+		{"delete_scope", func() error { _, e := s.DeleteResource("/", nil, false, "", false); return e }, true},
+		{"clear_whole_trash", func() error { _, e := s.ClearTrash(nil, false, ""); return e }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScopedDisk_replaceScope(t *testing.T) {
+	_, s := newScopedDisk(t, "/work")
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"move_over_scope", func() error { _, e := s.MoveResource("/c.txt", "/", nil, false, true); return e }},
+		{"copy_over_scope", func() error { _, e := s.CopyResource("/c.txt", "", nil, false, true); return e }},
+		{"upload_over_scope", func() error { _, e := s.GetResourceUploadLink("/", nil, true); return e }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if _, ok := err.(*yadisk.ScopeError); !ok {
+				t.Errorf("error = %v, want *ScopeError", err)
+			}
+		})
+	}
+}
+
+func TestScopedDisk_responses(t *testing.T) {
+	d, s := newScopedDisk(t, "app:/")
+	r, err := s.GetResource("/", nil, 0, 0, false, "", "name")
+	if err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	if r.Path != "disk:/" || len(r.Embedded.Items) != 2 || r.Embedded.Items[0].Path != "disk:/a.txt" || r.Embedded.Items[1].Path != "disk:/dir" {
+		t.Errorf("GetResource() = %v, %v", r.Path, r.Embedded.Items)
+	}
+
+	files, err := s.GetFlatFilesList([]string{"items.name"}, 0, "", 0, false, "", "")
+	if err != nil {
+		t.Fatalf("GetFlatFilesList() error = %v", err)
+	}
+	if len(files.Items) != 2 {
+		t.Errorf("GetFlatFilesList() items = %v", files.Items)
+	}
+
+	// Trash items deleted outside of the scope are neither listed nor restorable.
+	if _, err := d.DeleteResource("/secret.txt", nil, false, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DeleteResource("/a.txt", nil, false, "", false); err != nil {
+		t.Fatal(err)
+	}
+	trash, err := s.GetTrashResource("", nil, 0, 0, false, "", "")
+	if err != nil {
+		t.Fatalf("GetTrashResource() error = %v", err)
+	}
+	if len(trash.Embedded.Items) != 1 || trash.Embedded.Items[0].OriginPath != "disk:/a.txt" {
+		t.Fatalf("GetTrashResource() items = %v", trash.Embedded.Items)
+	}
+	if _, err := s.RestoreFromTrash("trash:/secret.txt", nil, false, "", false); err == nil {
+		t.Errorf("RestoreFromTrash() outside of the scope error = %v", err)
+	}
+	if _, err := s.RestoreFromTrash(trash.Embedded.Items[0].Path, nil, false, "", false); err != nil {
+		t.Errorf("RestoreFromTrash() error = %v", err)
+	}
+}
+
+func TestScopedDisk_subfolder(t *testing.T) {
+	_, s := newScopedDisk(t, "/work")
+	r, err := s.GetResource("c.txt", nil, 0, 0, false, "", "")
+	if err != nil || r.Path != "disk:/c.txt" {
+		t.Errorf("GetResource() = %v, error = %v", r, err)
+	}
+	if _, err := yadisk.NewScopedDisk(nil, "trash:/"); err == nil {
+		t.Errorf("NewScopedDisk() error = %v, wantErr true", err)
+	}
+}
@@ -0,0 +1,120 @@
+package yadisk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Host of the web links built by PublicLink.WebURL.
+const PublicWebHost = "disk.yandex.ru"
+
+var publicHostRegexp = regexp.MustCompile(`^(www\.)?(yadi\.sk|disk(\.360)?\.yandex\.[a-z]+(\.[a-z]+)?)$`)
+
+// PublicLink is a public resource as accepted by the methods taking a public key.
+type PublicLink struct {
+	// Public key or normalized public URL, e.g. "https://yadi.sk/d/<id>".
+	Key string
+	// Path inside the public folder, empty for the public resource itself.
+	Path string
+}
+
+// ParsePublicURL parses a public link or a public key.
+//
+// Accepted forms are the folder and file links https://disk.yandex.ru/d/<id> and https://yadi.sk/d/<id>,
+// the image links .../i/<id>, with or without the scheme and with other national domains of disk.yandex,
+// optionally followed by a path inside the folder, the legacy links .../public/?hash=<key>, and bare public keys.
+func ParsePublicURL(s string) (*PublicLink, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("yadisk: empty public link")
+	}
+	raw := s
+	if !strings.Contains(s, "://") {
+		host := s
+		if i := strings.IndexAny(host, "/?"); i >= 0 {
+			host = host[:i]
+		}
+		if !publicHostRegexp.MatchString(strings.ToLower(host)) {
+			// Public keys are base64 and may contain slashes, they are never prefixed with a known host.
+			return &PublicLink{Key: s}, nil
+		}
+		raw = "https://" + s
+	}
+	u, e := url.Parse(raw)
+	if e != nil {
+		return nil, fmt.Errorf("yadisk: public link %q: %v", s, e)
+	}
+	if !publicHostRegexp.MatchString(strings.ToLower(u.Hostname())) {
+		return nil, fmt.Errorf("yadisk: %q is not a Yandex Disk public link", s)
+	}
+	elems := strings.SplitN(strings.Trim(u.Path, "/"), "/", 3)
+	switch {
+	case len(elems) >= 2 && (elems[0] == "d" || elems[0] == "i") && elems[1] != "":
+		l := &PublicLink{Key: "https://yadi.sk/" + elems[0] + "/" + elems[1]}
+		if len(elems) == 3 && elems[2] != "" {
+			l.Path = path.Clean("/" + elems[2])
+		}
+		return l, nil
+	case elems[0] == "public" && u.Query().Get("hash") != "":
+		return &PublicLink{Key: u.Query().Get("hash"), Path: u.Query().Get("path")}, nil
+	}
+	return nil, fmt.Errorf("yadisk: unknown public link format %q", s)
+}
+
+// PublicLink returns the public link of a published resource, ok is false if it is not published.
+func (r *Resource) PublicLink() (l *PublicLink, ok bool) {
+	return publicLink(r.PublicURL, r.PublicKey, "")
+}
+
+// PublicLink returns the link of the public resource or of a resource inside a public folder.
+func (r *PublicResource) PublicLink() (l *PublicLink, ok bool) {
+	return publicLink(r.PublicURL, r.PublicKey, string(r.Path))
+}
+
+func publicLink(publicURL string, publicKey string, inner string) (*PublicLink, bool) {
+	var l *PublicLink
+	if publicURL != "" {
+		l, _ = ParsePublicURL(publicURL)
+	}
+	if l == nil && publicKey != "" {
+		l = &PublicLink{Key: publicKey}
+	}
+	if l == nil {
+		return nil, false
+	}
+	if inner != "" && inner != "/" {
+		l.Path = path.Clean("/" + inner)
+	}
+	return l, true
+}
+
+// WebURL returns the link opening the resource in a browser.
+func (l *PublicLink) WebURL() string {
+	if u, e := url.Parse(l.Key); e == nil && u.Host != "" {
+		elems := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(elems) == 2 {
+			web := "https://" + PublicWebHost + "/" + elems[0] + "/" + elems[1]
+			if l.Path != "" && l.Path != "/" {
+				web += Path(l.Path).Escape()[len(RootDisk):]
+			}
+			return web
+		}
+	}
+	values := url.Values{}
+	values.Set("hash", l.Key)
+	addValue(values, "path", l.Path)
+	return "https://" + PublicWebHost + "/public/?" + values.Encode()
+}
+
+// DownloadURL returns the URL of the API method returning the download link of the resource.
+// The request needs no token, the response is a Link with the direct download Href.
+func (l *PublicLink) DownloadURL() string {
+	values := url.Values{}
+	values.Set("public_key", l.Key)
+	addValue(values, "path", l.Path)
+	return BaseURL + "/v1/disk/public/resources/download?" + values.Encode()
+}
@@ -0,0 +1,77 @@
+package yadisk
+
+import "testing"
+
+func TestParsePublicURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    PublicLink
+		wantErr bool
+	}{
+		{"disk_d", "https://disk.yandex.ru/d/AbC-12_x", PublicLink{Key: "https://yadi.sk/d/AbC-12_x"}, false},
+		{"yadisk_d", "https://yadi.sk/d/AbC-12_x/", PublicLink{Key: "https://yadi.sk/d/AbC-12_x"}, false},
+		{"no_scheme", "yadi.sk/d/AbC", PublicLink{Key: "https://yadi.sk/d/AbC"}, false},
+		{"image", "https://disk.yandex.com/i/Img1", PublicLink{Key: "https://yadi.sk/i/Img1"}, false},
+		{"360", "http://disk.360.yandex.ru/d/X", PublicLink{Key: "https://yadi.sk/d/X"}, false},
+		{"national", "https://disk.yandex.com.tr/d/X?w=1", PublicLink{Key: "https://yadi.sk/d/X"}, false},
+		{"inner_path", "https://disk.yandex.ru/d/X/photos/my%20cat.jpg", PublicLink{Key: "https://yadi.sk/d/X", Path: "/photos/my cat.jpg"}, false},
+		{"legacy_hash", "https://disk.yandex.ru/public/?hash=a%2Bb%2Fc%3D", PublicLink{Key: "a+b/c="}, false},
+		{"bare_key", "IxJ6t1V0P4sB3Ex1FU+qYN0yfJw8k/7g0PGSR1G5TKE=", PublicLink{Key: "IxJ6t1V0P4sB3Ex1FU+qYN0yfJw8k/7g0PGSR1G5TKE="}, false},
+		{"other_host", "https://example.com/d/X", PublicLink{}, true},
+		{"no_id", "https://yadi.sk/d/", PublicLink{}, true},
+		{"client", "https://disk.yandex.ru/client/disk", PublicLink{}, true},
+		{"empty", " ", PublicLink{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicURL(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePublicURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("ParsePublicURL() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicLink_urls(t *testing.T) {
+	tests := []struct {
+		name     string
+		l        *PublicLink
+		web      string
+		download string
+	}{
+		{"url", &PublicLink{Key: "https://yadi.sk/d/X", Path: "/a b/c#1.txt"},
+			"https://disk.yandex.ru/d/X/a%20b/c%231.txt",
+			"https://cloud-api.yandex.net/v1/disk/public/resources/download?path=%2Fa+b%2Fc%231.txt&public_key=https%3A%2F%2Fyadi.sk%2Fd%2FX"},
+		{"key", &PublicLink{Key: "a+b/c="},
+			"https://disk.yandex.ru/public/?hash=a%2Bb%2Fc%3D",
+			"https://cloud-api.yandex.net/v1/disk/public/resources/download?public_key=a%2Bb%2Fc%3D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.WebURL(); got != tt.web {
+				t.Errorf("PublicLink.WebURL() = %v, want %v", got, tt.web)
+			}
+			if got := tt.l.DownloadURL(); got != tt.download {
+				t.Errorf("PublicLink.DownloadURL() = %v, want %v", got, tt.download)
+			}
+		})
+	}
+}
+
+func TestPublicResource_PublicLink(t *testing.T) {
+	r := &PublicResource{baseResource: baseResource{PublicKey: "key", Path: "/sub/a.txt"}}
+	if l, ok := r.PublicLink(); !ok || l.Key != "key" || l.Path != "/sub/a.txt" {
+		t.Errorf("PublicResource.PublicLink() = %+v, %v", l, ok)
+	}
+	res := &Resource{baseResource: baseResource{PublicURL: "https://disk.yandex.ru/d/X", PublicKey: "key"}}
+	if l, ok := res.PublicLink(); !ok || l.Key != "https://yadi.sk/d/X" || l.Path != "" {
+		t.Errorf("Resource.PublicLink() = %+v, %v", l, ok)
+	}
+	if _, ok := new(Resource).PublicLink(); ok {
+		t.Errorf("Resource.PublicLink() of an unpublished resource ok = true")
+	}
+}
@@ -0,0 +1,261 @@
+package yadisk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Version of the JSON Lines format of snapshots written by Snapshot.WriteTo.
+const SnapshotVersion = 1
+
+// SnapshotEntry is the state of a resource in a Snapshot.
+type SnapshotEntry struct {
+	Path       Path         `json:"path"`
+	ResourceID string       `json:"resource_id"`
+	Type       ResourceType `json:"type"`
+	Size       int64        `json:"size,omitempty"`
+	Md5        string       `json:"md5,omitempty"`
+	Sha256     string       `json:"sha256,omitempty"`
+	Modified   time.Time    `json:"modified"`
+	Revision   int64        `json:"revision,omitempty"`
+}
+
+// Snapshot is the state of the tree below Root at a revision of the Disk.
+type Snapshot struct {
+	Root Path `json:"root"`
+	// Revision of the Disk read before the tree was listed, changes made while listing may be included.
+	Revision int64     `json:"revision"`
+	Taken    time.Time `json:"taken"`
+	// Resources below Root, without Root itself.
+	Entries map[Path]*SnapshotEntry `json:"entries"`
+}
+
+// snapshotHeader is the first line of a snapshot in the JSON Lines format, the entries follow one per line.
+type snapshotHeader struct {
+	Version  int       `json:"version"`
+	Root     Path      `json:"root"`
+	Revision int64     `json:"revision"`
+	Taken    time.Time `json:"taken"`
+	// Number of entries, a shorter file was truncated.
+	Entries int `json:"entries"`
+}
+
+// EventType is the kind of a change between two snapshots.
+type EventType string
+
+// Event types.
+const (
+	EventCreated  EventType = "created"
+	EventModified EventType = "modified"
+	EventDeleted  EventType = "deleted"
+	EventMoved    EventType = "moved"
+)
+
+// Event is a change of a resource between two snapshots.
+type Event struct {
+	Type EventType
+	// Path of the resource, its new path if it was moved.
+	Path Path
+	// Path the resource was moved from.
+	OldPath Path
+	// State of the resource before and after the change, Old is nil if it was created and New if it was deleted.
+	Old *SnapshotEntry
+	New *SnapshotEntry
+}
+
+// TakeSnapshot lists the tree below root.
+func TakeSnapshot(ctx context.Context, yad YaDisk, root Path) (*Snapshot, error) {
+	root, e := ParsePath(string(root))
+	if e != nil {
+		return nil, e
+	}
+	disk, e := yad.GetDisk([]string{"revision"})
+	if e != nil {
+		return nil, e
+	}
+	s := &Snapshot{Root: root, Revision: disk.Revision, Taken: time.Now().UTC(), Entries: make(map[Path]*SnapshotEntry)}
+	fields := embeddedFields("path", "type", "size", "md5", "sha256", "modified", "revision", "resource_id")
+	e = walk(ctx, yad, root, fields, func(r *Resource) error {
+		s.Entries[Path(r.Path)] = &SnapshotEntry{
+			Path:       Path(r.Path),
+			ResourceID: r.ResourceID,
+			Type:       r.Type,
+			Size:       r.Size,
+			Md5:        r.Md5,
+			Sha256:     r.Sha256,
+			Modified:   r.Modified.Time,
+			Revision:   r.Revision,
+		}
+		return nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return s, nil
+}
+
+// WriteTo writes the snapshot as JSON Lines: a header with the version of the format, then the entries sorted by path.
+func (s *Snapshot) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	enc := json.NewEncoder(bw)
+	e := enc.Encode(&snapshotHeader{Version: SnapshotVersion, Root: s.Root, Revision: s.Revision, Taken: s.Taken, Entries: len(s.Entries)})
+	if e != nil {
+		return cw.n, e
+	}
+	for _, entry := range s.sorted() {
+		if e := enc.Encode(entry); e != nil {
+			return cw.n, e
+		}
+	}
+	e = bw.Flush()
+	return cw.n, e
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, e := c.w.Write(p)
+	c.n += int64(n)
+	return n, e
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.WriteTo.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	h := new(snapshotHeader)
+	if e := dec.Decode(h); e != nil {
+		return nil, fmt.Errorf("yadisk: snapshot header: %v", e)
+	}
+	if h.Version != SnapshotVersion {
+		return nil, fmt.Errorf("yadisk: unsupported snapshot version %d", h.Version)
+	}
+	s := &Snapshot{Root: h.Root, Revision: h.Revision, Taken: h.Taken, Entries: make(map[Path]*SnapshotEntry, h.Entries)}
+	for {
+		entry := new(SnapshotEntry)
+		e := dec.Decode(entry)
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return nil, fmt.Errorf("yadisk: snapshot entry %d: %v", len(s.Entries)+1, e)
+		}
+		s.Entries[entry.Path] = entry
+	}
+	if len(s.Entries) != h.Entries {
+		return nil, fmt.Errorf("yadisk: snapshot has %d entries, want %d", len(s.Entries), h.Entries)
+	}
+	return s, nil
+}
+
+// sorted returns the entries sorted by path.
+func (s *Snapshot) sorted() []*SnapshotEntry {
+	entries := make([]*SnapshotEntry, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// DiffLive takes a snapshot of the tree of s and returns the changes since s together with the new snapshot.
+func DiffLive(ctx context.Context, yad YaDisk, s *Snapshot) ([]Event, *Snapshot, error) {
+	live, e := TakeSnapshot(ctx, yad, s.Root)
+	if e != nil {
+		return nil, nil, e
+	}
+	return DiffSnapshots(s, live), live, nil
+}
+
+// DiffSnapshots returns the changes from before to after, sorted by path.
+//
+// Resources are matched by ResourceID first, so a resource moved away and replaced by a new one at its
+// path is reported as moved and created, the remaining ones by path. The resources below a moved folder
+// that kept their place in it are not reported. Folders are reported modified only if their ResourceID
+// changed, a change of their contents is reported for the contents.
+func DiffSnapshots(before *Snapshot, after *Snapshot) []Event {
+	var events []Event
+	byID := make(map[string]*SnapshotEntry)
+	for _, o := range before.Entries {
+		if o.ResourceID != "" {
+			byID[o.ResourceID] = o
+		}
+	}
+	matched := make(map[Path]bool)
+	moved := make(map[Path]Path)
+	var unmatched []Path
+	for p, n := range after.Entries {
+		o, ok := byID[n.ResourceID]
+		if !ok || n.ResourceID == "" {
+			unmatched = append(unmatched, p)
+			continue
+		}
+		matched[o.Path] = true
+		if o.Path != p {
+			moved[o.Path] = p
+			events = append(events, Event{Type: EventMoved, Path: p, OldPath: o.Path, Old: o, New: n})
+		}
+		if changed(o, n) {
+			events = append(events, Event{Type: EventModified, Path: p, Old: o, New: n})
+		}
+	}
+	for _, p := range unmatched {
+		n := after.Entries[p]
+		o, ok := before.Entries[p]
+		if !ok || matched[p] {
+			events = append(events, Event{Type: EventCreated, Path: p, New: n})
+			continue
+		}
+		matched[p] = true
+		if changed(o, n) {
+			events = append(events, Event{Type: EventModified, Path: p, Old: o, New: n})
+		}
+	}
+	for p, o := range before.Entries {
+		if !matched[p] {
+			events = append(events, Event{Type: EventDeleted, Path: p, Old: o})
+		}
+	}
+
+	// Drop the moves implied by the move of a parent folder.
+	kept := events[:0]
+	for _, ev := range events {
+		if ev.Type != EventMoved || !movedWithParent(moved, ev.OldPath, ev.Path) {
+			kept = append(kept, ev)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Path < kept[j].Path
+	})
+	return kept
+}
+
+// changed reports whether a resource at the same path was modified.
+func changed(o *SnapshotEntry, n *SnapshotEntry) bool {
+	if o.Type != n.Type {
+		return true
+	}
+	if o.Type == ResourceTypeDir {
+		return o.ResourceID != n.ResourceID && o.ResourceID != "" && n.ResourceID != ""
+	}
+	return o.Md5 != n.Md5 || o.Sha256 != n.Sha256 || o.Size != n.Size || !o.Modified.Equal(n.Modified)
+}
+
+func movedWithParent(moved map[Path]Path, from Path, to Path) bool {
+	fromDir, toDir := from.Dir(), to.Dir()
+	if from.Base() != to.Base() || fromDir.IsRoot() {
+		return false
+	}
+	return moved[fromDir] == toDir
+}
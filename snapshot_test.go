@@ -0,0 +1,122 @@
+package yadisk
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSnapshot(entries ...*SnapshotEntry) *Snapshot {
+	s := &Snapshot{Root: "disk:/", Entries: make(map[Path]*SnapshotEntry)}
+	for _, e := range entries {
+		s.Entries[e.Path] = e
+	}
+	return s
+}
+
+func snapshotFile(p Path, id string, md5 string) *SnapshotEntry {
+	return &SnapshotEntry{Path: p, ResourceID: id, Type: ResourceTypeFile, Md5: md5, Modified: time.Unix(0, 0).UTC()}
+}
+
+func snapshotDir(p Path, id string) *SnapshotEntry {
+	return &SnapshotEntry{Path: p, ResourceID: id, Type: ResourceTypeDir}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	tests := []struct {
+		name   string
+		before *Snapshot
+		after  *Snapshot
+		want   []string
+	}{
+		{"none", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotFile("disk:/a", "1", "x")), nil},
+		{"created", testSnapshot(), testSnapshot(snapshotFile("disk:/a", "1", "x")), []string{"created disk:/a"}},
+		{"deleted", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(), []string{"deleted disk:/a"}},
+		{"modified", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotFile("disk:/a", "2", "y")), []string{"modified disk:/a"}},
+		{"moved", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotFile("disk:/b", "1", "x")), []string{"moved disk:/a disk:/b"}},
+		{"moved_and_modified", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotFile("disk:/b", "1", "y")),
+			[]string{"moved disk:/a disk:/b", "modified disk:/b"}},
+		{"moved_and_replaced", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotFile("disk:/b", "1", "x"), snapshotFile("disk:/a", "2", "y")),
+			[]string{"created disk:/a", "moved disk:/a disk:/b"}},
+		{"swapped", testSnapshot(snapshotFile("disk:/a", "1", "x"), snapshotFile("disk:/b", "2", "y")),
+			testSnapshot(snapshotFile("disk:/a", "2", "y"), snapshotFile("disk:/b", "1", "x")),
+			[]string{"moved disk:/b disk:/a", "moved disk:/a disk:/b"}},
+		{"moved_folder",
+			testSnapshot(snapshotDir("disk:/d", "1"), snapshotDir("disk:/d/s", "2"), snapshotFile("disk:/d/s/f", "3", "x"), snapshotFile("disk:/d/g", "4", "x")),
+			testSnapshot(snapshotDir("disk:/e", "1"), snapshotDir("disk:/e/s", "2"), snapshotFile("disk:/e/s/f", "3", "x"), snapshotFile("disk:/g", "4", "x")),
+			[]string{"moved disk:/d disk:/e", "moved disk:/d/g disk:/g"}},
+		{"folder_contents", testSnapshot(snapshotDir("disk:/d", "1"), snapshotFile("disk:/d/f", "2", "x")), testSnapshot(snapshotDir("disk:/d", "1")),
+			[]string{"deleted disk:/d/f"}},
+		{"replaced_by_folder", testSnapshot(snapshotFile("disk:/a", "1", "x")), testSnapshot(snapshotDir("disk:/a", "2")), []string{"modified disk:/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ev := range DiffSnapshots(tt.before, tt.after) {
+				if ev.Type == EventMoved {
+					got = append(got, fmt.Sprintf("%s %s %s", ev.Type, ev.OldPath, ev.Path))
+				} else {
+					got = append(got, fmt.Sprintf("%s %s", ev.Type, ev.Path))
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("DiffSnapshots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshot_WriteTo(t *testing.T) {
+	s := testSnapshot(snapshotDir("disk:/d", "1"), snapshotFile("disk:/d/f", "2", "x"), snapshotFile("disk:/a", "3", "y"))
+	s.Revision = 7
+	s.Taken = time.Unix(100, 0).UTC()
+	s.Entries["disk:/a"].Sha256 = "z"
+	s.Entries["disk:/a"].Revision = 5
+
+	var buf bytes.Buffer
+	if n, err := s.WriteTo(&buf); err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo() = %d, error = %v, want %d", n, err, buf.Len())
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], `{"version":1,`) || !strings.Contains(lines[1], `"path":"disk:/a"`) {
+		t.Fatalf("WriteTo() = %s", buf.String())
+	}
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("ReadSnapshot() = %+v, want %+v", got, s)
+	}
+
+	// Larger than the buffer of the writer.
+	large := testSnapshot()
+	for i := 0; i < 100; i++ {
+		f := snapshotFile(Path(fmt.Sprintf("disk:/f%03d", i)), fmt.Sprint(i), "x")
+		large.Entries[f.Path] = f
+	}
+	var largeBuf bytes.Buffer
+	if n, err := large.WriteTo(&largeBuf); err != nil || n != int64(largeBuf.Len()) || n <= 4096 {
+		t.Errorf("WriteTo() of %d entries = %d, error = %v, want %d", len(large.Entries), n, err, largeBuf.Len())
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"version", `{"version":2,"root":"disk:/","entries":0}`},
+		{"truncated", strings.Join(lines[:3], "\n")},
+		{"entry", lines[0] + "\n{"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSnapshot(strings.NewReader(tt.data)); err == nil {
+				t.Error("ReadSnapshot() error = nil")
+			}
+		})
+	}
+}
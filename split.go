@@ -0,0 +1,488 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Suffix of the manifest of a split file, the manifest of "disk:/movie.mkv" is "disk:/movie.mkv.split.json".
+const SplitManifestSuffix = ".split.json"
+
+// Version of the manifest written by UploadSplit.
+const SplitManifestVersion = 1
+
+// SplitManifest describes a file stored as numbered parts in the folder of the manifest.
+type SplitManifest struct {
+	Version  int    `json:"version"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	PartSize int64  `json:"part_size"`
+	// Hashes of the whole file.
+	Md5    string      `json:"md5"`
+	Sha256 string      `json:"sha256"`
+	Parts  []SplitPart `json:"parts"`
+}
+
+// SplitPart is a part of a split file.
+type SplitPart struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Md5    string `json:"md5"`
+	Sha256 string `json:"sha256"`
+}
+
+// Options of UploadSplit, DownloadSplit and OpenSplit.
+type SplitOptions struct {
+	// Maximum size of a part, the smaller of Disk.MaxFileSize and MaxFileUploadSize by default.
+	PartSize int64
+	// Replace an existing file or parts.
+	Overwrite bool
+	// Client of the upload and download links, http.DefaultClient by default.
+	HTTPClient *http.Client
+	// Checks the space of the Disk before UploadSplit, if set. Files that cannot fit fail with a *QuotaError.
+	Quota *QuotaGuard
+}
+
+// UploadSplit uploads size bytes of r to the file at p, creating the missing folders. A file larger than a part is stored as the parts
+// "<name>.part0001", "<name>.part0002"... next to a manifest at p+SplitManifestSuffix, which is uploaded last.
+// The manifest is nil if the file was uploaded whole.
+//
+// An existing file at p, whole or split, is replaced only with Overwrite. The new parts do not reuse the names of
+// the old ones, "<name>.1.part0001" instead, and the old file is deleted once the new one is complete, so a failed
+// overwrite leaves it intact.
+func UploadSplit(ctx context.Context, yad YaDisk, p Path, r io.Reader, size int64, opts *SplitOptions) (*SplitManifest, error) {
+	o := SplitOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if size < 0 {
+		return nil, errors.New("yadisk: unknown size of a split upload")
+	}
+	p, e := ParsePath(string(p))
+	if e != nil {
+		return nil, e
+	}
+	partSize := o.PartSize
+	if partSize <= 0 {
+		disk, e := yad.GetDisk([]string{"max_file_size"})
+		if e != nil {
+			return nil, e
+		}
+		partSize = MaxFileUploadSize
+		if disk.MaxFileSize > 0 && disk.MaxFileSize < partSize {
+			partSize = disk.MaxFileSize
+		}
+	}
+	client := httpClient(o.HTTPClient)
+	old, e := ReadSplitManifest(ctx, yad, p, client)
+	if e != nil && !IsErrorID(e, ErrorIDNotFound) {
+		return nil, e
+	}
+	plain, e := GetResourceWithOptions(yad, string(p), &ResourceOptions{Fields: []string{"type"}})
+	if IsErrorID(e, ErrorIDNotFound) {
+		plain, e = nil, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+	if plain != nil && plain.Type != ResourceTypeFile {
+		return nil, fmt.Errorf("yadisk: %q is not a file", p)
+	}
+	if (old != nil || plain != nil) && !o.Overwrite {
+		return nil, fmt.Errorf("yadisk: %s already exists", p)
+	}
+	if o.Quota != nil {
+		release, e := reserveSplit(yad, p, size, partSize, &o)
+		if e != nil {
+			return nil, e
+		}
+		defer release()
+	}
+	if e := MkdirAll(ctx, yad, p.Dir()); e != nil {
+		return nil, e
+	}
+	if size <= partSize {
+		if e := uploadPart(ctx, yad, client, p, r, size, o.Overwrite); e != nil {
+			return nil, e
+		}
+		if old != nil {
+			if e := removeSplitFile(ctx, yad, p+SplitManifestSuffix); e != nil {
+				return nil, e
+			}
+		}
+		return nil, removeSplitParts(ctx, yad, p, old, nil)
+	}
+
+	m := &SplitManifest{Version: SplitManifestVersion, Name: p.Base(), Size: size, PartSize: partSize}
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	r = io.TeeReader(r, io.MultiWriter(md5Hash, sha256Hash))
+	for i := 0; int64(i)*partSize < size; i++ {
+		part := SplitPart{Name: splitPartName(m.Name, i, old), Size: partSize}
+		if rest := size - int64(i)*partSize; rest < partSize {
+			part.Size = rest
+		}
+		partMd5, partSha256 := md5.New(), sha256.New()
+		body := io.TeeReader(io.LimitReader(r, part.Size), io.MultiWriter(partMd5, partSha256))
+		if e := uploadPart(ctx, yad, client, p.Dir().Join(part.Name), body, part.Size, o.Overwrite); e != nil {
+			return nil, e
+		}
+		part.Md5, part.Sha256 = hex.EncodeToString(partMd5.Sum(nil)), hex.EncodeToString(partSha256.Sum(nil))
+		m.Parts = append(m.Parts, part)
+	}
+	m.Md5, m.Sha256 = hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha256Hash.Sum(nil))
+
+	data, e := json.MarshalIndent(m, "", "  ")
+	if e != nil {
+		return nil, e
+	}
+	if e := uploadPart(ctx, yad, client, p+SplitManifestSuffix, bytes.NewReader(data), int64(len(data)), o.Overwrite); e != nil {
+		return nil, e
+	}
+	if plain != nil {
+		if e := removeSplitFile(ctx, yad, p); e != nil {
+			return nil, e
+		}
+	}
+	return m, removeSplitParts(ctx, yad, p, old, m)
+}
+
+// splitPartName returns the name of the ith part of a split file, one not taken by a part of old.
+func splitPartName(name string, i int, old *SplitManifest) string {
+	part := fmt.Sprintf("%s.part%04d", name, i+1)
+	if old != nil {
+		for _, p := range old.Parts {
+			if p.Name == part {
+				return fmt.Sprintf("%s.1.part%04d", name, i+1)
+			}
+		}
+	}
+	return part
+}
+
+// removeSplitParts deletes the parts of the old manifest that are not in the new one, nil if the file is whole.
+func removeSplitParts(ctx context.Context, yad YaDisk, p Path, old *SplitManifest, m *SplitManifest) error {
+	if old == nil {
+		return nil
+	}
+	used := make(map[string]bool)
+	if m != nil {
+		for _, part := range m.Parts {
+			used[part.Name] = true
+		}
+	}
+	for _, part := range old.Parts {
+		if used[part.Name] {
+			continue
+		}
+		if e := removeSplitFile(ctx, yad, p.Dir().Join(part.Name)); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// removeSplitFile deletes the file at p permanently, a missing file is not an error.
+func removeSplitFile(ctx context.Context, yad YaDisk, p Path) error {
+	l, e := yad.DeleteResource(string(p), nil, false, "", true)
+	if IsErrorID(e, ErrorIDNotFound) {
+		return nil
+	}
+	if e != nil {
+		return e
+	}
+	return WaitOperation(ctx, yad, l, 0)
+}
+
+// reserveSplit reserves the space of the upload with the quota guard, counting the whole file it replaces.
+func reserveSplit(yad YaDisk, p Path, size int64, partSize int64, o *SplitOptions) (func(), error) {
+	if size > partSize {
+		// The old file is deleted after the parts are uploaded.
+		return o.Quota.reserve(p, size, partSize, 0)
+	}
+	replaced := int64(0)
+	if o.Overwrite {
+		n, e := fileSize(yad, p)
+		if e != nil {
+			return nil, e
+		}
+		replaced = n
+	}
+	return o.Quota.reserve(p, size, size, replaced)
+}
+
+func uploadPart(ctx context.Context, yad YaDisk, client *http.Client, p Path, r io.Reader, size int64, overwrite bool) error {
+	link, e := yad.GetResourceUploadLink(string(p), nil, overwrite)
+	if e != nil {
+		return e
+	}
+	return streamUpload(ctx, client, link, r, size)
+}
+
+// ReadSplitManifest returns the manifest of the split file at p, an error with ErrorIDNotFound if it was not split.
+func ReadSplitManifest(ctx context.Context, yad YaDisk, p Path, client *http.Client) (*SplitManifest, error) {
+	p, e := ParsePath(string(p))
+	if e != nil {
+		return nil, e
+	}
+	link, e := yad.GetResourceDownloadLink(string(p)+SplitManifestSuffix, nil)
+	if e != nil {
+		return nil, e
+	}
+	resp, e := openHref(ctx, httpClient(client), link.Href)
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+	data, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return nil, e
+	}
+	m := new(SplitManifest)
+	if e := json.Unmarshal(data, m); e != nil {
+		return nil, fmt.Errorf("yadisk: manifest of %s: %v", p, e)
+	}
+	if m.Version != SplitManifestVersion {
+		return nil, fmt.Errorf("yadisk: unsupported version %d of the manifest of %s", m.Version, p)
+	}
+	var size int64
+	for _, part := range m.Parts {
+		if e := checkName(part.Name); e != nil {
+			return nil, e
+		}
+		size += part.Size
+	}
+	if size != m.Size {
+		return nil, fmt.Errorf("yadisk: manifest of %s: parts of %d bytes, want %d", p, size, m.Size)
+	}
+	return m, nil
+}
+
+// DownloadSplit writes the file at p into w, reassembled from its parts if it was split, and returns the number of bytes written.
+// The parts and the whole file are verified against the hashes of the manifest.
+func DownloadSplit(ctx context.Context, yad YaDisk, p Path, w io.Writer, opts *SplitOptions) (int64, error) {
+	o := SplitOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	client := httpClient(o.HTTPClient)
+	m, e := ReadSplitManifest(ctx, yad, p, client)
+	if IsErrorID(e, ErrorIDNotFound) {
+		return downloadPart(ctx, yad, client, p, w, nil)
+	}
+	if e != nil {
+		return 0, e
+	}
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	w = io.MultiWriter(w, md5Hash, sha256Hash)
+	var written int64
+	for i := range m.Parts {
+		n, e := downloadPart(ctx, yad, client, p.Dir().Join(m.Parts[i].Name), w, &m.Parts[i])
+		written += n
+		if e != nil {
+			return written, e
+		}
+	}
+	if e := verifySplit(md5Hash, sha256Hash, m.Md5, m.Sha256); e != nil {
+		return written, fmt.Errorf("yadisk: %s: %v", p, e)
+	}
+	return written, nil
+}
+
+// downloadPart copies the file at p into w, verifying it against part if it is not nil.
+func downloadPart(ctx context.Context, yad YaDisk, client *http.Client, p Path, w io.Writer, part *SplitPart) (int64, error) {
+	link, e := yad.GetResourceDownloadLink(string(p), nil)
+	if e != nil {
+		return 0, e
+	}
+	resp, e := openHref(ctx, client, link.Href)
+	if e != nil {
+		return 0, e
+	}
+	defer resp.Body.Close()
+	if part == nil {
+		return io.Copy(w, resp.Body)
+	}
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	n, e := io.Copy(io.MultiWriter(w, md5Hash, sha256Hash), resp.Body)
+	if e != nil {
+		return n, e
+	}
+	if n != part.Size {
+		return n, fmt.Errorf("yadisk: %s: %d bytes, want %d", p, n, part.Size)
+	}
+	if e := verifySplit(md5Hash, sha256Hash, part.Md5, part.Sha256); e != nil {
+		return n, fmt.Errorf("yadisk: %s: %v", p, e)
+	}
+	return n, nil
+}
+
+// verifySplit compares the hashes of the data with the hashes of the manifest, empty ones are not checked.
+func verifySplit(md5Hash hash.Hash, sha256Hash hash.Hash, wantMd5 string, wantSha256 string) error {
+	if sum := hex.EncodeToString(md5Hash.Sum(nil)); wantMd5 != "" && sum != wantMd5 {
+		return fmt.Errorf("md5 %s, want %s", sum, wantMd5)
+	}
+	if sum := hex.EncodeToString(sha256Hash.Sum(nil)); wantSha256 != "" && sum != wantSha256 {
+		return fmt.Errorf("sha256 %s, want %s", sum, wantSha256)
+	}
+	return nil
+}
+
+// SplitReader is a file reassembled from its parts, with random access. Reads fetch ranges of the parts,
+// the hashes are not verified, see DownloadSplit.
+type SplitReader struct {
+	ctx    context.Context
+	yad    YaDisk
+	client *http.Client
+	dir    Path
+	parts  []splitReaderPart
+	size   int64
+
+	// Guards the links of the parts.
+	linksMu sync.Mutex
+
+	mu     sync.Mutex
+	offset int64
+}
+
+type splitReaderPart struct {
+	name  string
+	start int64
+	size  int64
+	// Download link, fetched on the first read of the part.
+	href string
+}
+
+// OpenSplit returns the file at p for random access, reassembled from its parts if it was split.
+func OpenSplit(ctx context.Context, yad YaDisk, p Path, opts *SplitOptions) (*SplitReader, error) {
+	o := SplitOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	p, e := ParsePath(string(p))
+	if e != nil {
+		return nil, e
+	}
+	s := &SplitReader{ctx: ctx, yad: yad, client: httpClient(o.HTTPClient), dir: p.Dir()}
+	m, e := ReadSplitManifest(ctx, yad, p, s.client)
+	switch {
+	case IsErrorID(e, ErrorIDNotFound):
+		r, e := GetResourceWithOptions(yad, string(p), &ResourceOptions{Fields: []string{"type", "size"}})
+		if e != nil {
+			return nil, e
+		}
+		if r.Type != ResourceTypeFile {
+			return nil, fmt.Errorf("yadisk: %q is not a file", p)
+		}
+		s.parts = []splitReaderPart{{name: p.Base(), size: r.Size}}
+		s.size = r.Size
+	case e != nil:
+		return nil, e
+	default:
+		for _, part := range m.Parts {
+			s.parts = append(s.parts, splitReaderPart{name: part.Name, start: s.size, size: part.Size})
+			s.size += part.Size
+		}
+	}
+	return s, nil
+}
+
+// Size returns the size of the whole file.
+func (s *SplitReader) Size() int64 {
+	return s.size
+}
+
+// ReadAt reads from off, across the parts it spans.
+func (s *SplitReader) ReadAt(p []byte, off int64) (n int, e error) {
+	if off < 0 {
+		return 0, errors.New("yadisk: negative offset")
+	}
+	i := sort.Search(len(s.parts), func(i int) bool { return s.parts[i].start+s.parts[i].size > off })
+	for ; n < len(p) && i < len(s.parts); i++ {
+		part := &s.parts[i]
+		want := p[n:]
+		if rest := part.start + part.size - off; int64(len(want)) > rest {
+			want = want[:rest]
+		}
+		href, e := s.href(part)
+		if e != nil {
+			return n, e
+		}
+		m, e := (&hrefReaderAt{ctx: s.ctx, client: s.client, href: href}).ReadAt(want, off-part.start)
+		n += m
+		off += int64(m)
+		if m < len(want) {
+			if e == nil || e == io.EOF {
+				e = io.ErrUnexpectedEOF
+			}
+			return n, e
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read reads from the current offset.
+func (s *SplitReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.offset >= s.size {
+		return 0, io.EOF
+	}
+	n, e := s.ReadAt(p, s.offset)
+	s.offset += int64(n)
+	if e == io.EOF && n > 0 {
+		e = nil
+	}
+	return n, e
+}
+
+// Seek sets the offset of the next Read.
+func (s *SplitReader) Seek(offset int64, whence int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, errors.New("yadisk: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("yadisk: negative offset")
+	}
+	s.offset = offset
+	return offset, nil
+}
+
+func (s *SplitReader) href(part *splitReaderPart) (string, error) {
+	s.linksMu.Lock()
+	href := part.href
+	s.linksMu.Unlock()
+	if href != "" {
+		return href, nil
+	}
+	link, e := s.yad.GetResourceDownloadLink(string(s.dir.Join(part.name)), nil)
+	if e != nil {
+		return "", e
+	}
+	s.linksMu.Lock()
+	part.href = link.Href
+	s.linksMu.Unlock()
+	return link.Href, nil
+}
@@ -0,0 +1,162 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func TestUploadSplit(t *testing.T) {
+	data := make([]byte, 2500)
+	rand.Read(data)
+	tests := []struct {
+		name    string
+		size    int
+		parts   int
+		corrupt bool
+		wantErr bool
+	}{
+		{"split", 2500, 3, false, false},
+		{"whole_parts", 2000, 2, false, false},
+		{"fits", 1000, 0, false, false},
+		{"corrupted_part", 2500, 3, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := yadisktest.New(&yadisktest.Options{MaxFileSize: 1000})
+			opts := &yadisk.SplitOptions{HTTPClient: d.HTTPClient()}
+			want := data[:tt.size]
+
+			m, err := yadisk.UploadSplit(ctx, d, "/big/data.bin", bytes.NewReader(want), int64(tt.size), opts)
+			if err != nil {
+				t.Fatalf("UploadSplit() error = %v", err)
+			}
+			if (m == nil) != (tt.parts == 0) || (m != nil && len(m.Parts) != tt.parts) {
+				t.Fatalf("UploadSplit() = %+v, want %d parts", m, tt.parts)
+			}
+			if tt.corrupt {
+				_ = d.WriteFile("/big/data.bin.part0002", bytes.Repeat([]byte{0}, 1000))
+			}
+
+			buf := new(bytes.Buffer)
+			n, err := yadisk.DownloadSplit(ctx, d, "/big/data.bin", buf, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadSplit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if n != int64(tt.size) || !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("DownloadSplit() = %d bytes, want %d", n, tt.size)
+			}
+
+			r, err := yadisk.OpenSplit(ctx, d, "/big/data.bin", opts)
+			if err != nil || r.Size() != int64(tt.size) {
+				t.Fatalf("OpenSplit() = %v, error = %v", r, err)
+			}
+			part := make([]byte, 600)
+			wantPart := want[700:]
+			if len(wantPart) > len(part) {
+				wantPart = wantPart[:len(part)]
+			}
+			if n, err := r.ReadAt(part, 700); !bytes.Equal(part[:n], wantPart) || (n < len(part) && err != io.EOF) {
+				t.Errorf("ReadAt() = %d, error = %v", n, err)
+			}
+			if _, err := r.Seek(-800, io.SeekEnd); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := ioutil.ReadAll(r); err != nil || !bytes.Equal(got, want[tt.size-800:]) {
+				t.Errorf("Read() after Seek() = %d bytes, error = %v", len(got), err)
+			}
+		})
+	}
+}
+
+func TestUploadSplit_overwrite(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    int
+		size      int
+		short     bool
+		overwrite bool
+		parts     []string
+		wantErr   bool
+	}{
+		{"whole", 2500, 800, false, true, nil, false},
+		{"fewer_parts", 2500, 1500, false, true, []string{"data.bin.1.part0001", "data.bin.1.part0002"}, false},
+		{"more_parts", 2500, 3000, false, true, []string{"data.bin.1.part0001", "data.bin.1.part0002", "data.bin.1.part0003"}, false},
+		{"no_overwrite", 2500, 800, false, false, []string{"data.bin.part0001", "data.bin.part0002", "data.bin.part0003"}, true},
+		{"failed", 2500, 3000, true, true, []string{"data.bin.part0001", "data.bin.part0002", "data.bin.part0003", "data.bin.1.part0001"}, true},
+		{"plain_file", 800, 1500, false, true, []string{"data.bin.part0001", "data.bin.part0002"}, false},
+		{"plain_file_no_overwrite", 800, 1500, false, false, nil, true},
+	}
+	names := []string{"data.bin.part0001", "data.bin.part0002", "data.bin.part0003", "data.bin.1.part0001", "data.bin.1.part0002", "data.bin.1.part0003"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := yadisktest.New(&yadisktest.Options{MaxFileSize: 1000})
+			before := make([]byte, tt.before)
+			rand.Read(before)
+			if _, err := yadisk.UploadSplit(ctx, d, "/big/data.bin", bytes.NewReader(before), int64(len(before)), &yadisk.SplitOptions{HTTPClient: d.HTTPClient()}); err != nil {
+				t.Fatal(err)
+			}
+			want := make([]byte, tt.size)
+			rand.Read(want)
+			opts := &yadisk.SplitOptions{HTTPClient: d.HTTPClient(), Overwrite: tt.overwrite}
+
+			var r io.Reader = bytes.NewReader(want)
+			if tt.short {
+				// The upload fails in the second part.
+				r = io.MultiReader(bytes.NewReader(want[:1500]), failingReader{})
+			}
+			_, err := yadisk.UploadSplit(ctx, d, "/big/data.bin", r, int64(tt.size), opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UploadSplit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				want = before
+			}
+			buf := new(bytes.Buffer)
+			if _, err := yadisk.DownloadSplit(ctx, d, "/big/data.bin", buf, opts); err != nil || !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("DownloadSplit() = %d bytes, error = %v, want %d bytes", buf.Len(), err, len(want))
+			}
+			if r, err := yadisk.OpenSplit(ctx, d, "/big/data.bin", opts); err != nil || r.Size() != int64(len(want)) {
+				t.Errorf("OpenSplit() = %v, error = %v, want size %d", r, err, len(want))
+			}
+			_, err = d.GetResource("/big/data.bin", nil, 0, 0, false, "", "")
+			if exists, want := err == nil, len(want) <= 1000; exists != want {
+				t.Errorf("data.bin exists = %v, want %v", exists, want)
+			}
+			for _, name := range names {
+				_, err := d.GetResource("/big/"+name, nil, 0, 0, false, "", "")
+				if exists, want := err == nil, contains(tt.parts, name); exists != want {
+					t.Errorf("%s exists = %v, want %v", name, exists, want)
+				}
+			}
+		})
+	}
+}
+
+// failingReader fails every read.
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
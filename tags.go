@@ -0,0 +1,138 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// Key of the custom properties reserved for the tags of a resource.
+const TagsProperty = "yadisk_tags"
+
+// Tags returns the sorted tags of the resource.
+func (r *Resource) Tags() []string {
+	var props struct {
+		Tags []string `json:"yadisk_tags"`
+	}
+	if e := r.DecodeCustomProperties(&props); e != nil {
+		return nil
+	}
+	// Tags written by other clients may be in any order.
+	sort.Strings(props.Tags)
+	return props.Tags
+}
+
+// Tags returns the sorted tags of the resource at p.
+func Tags(ctx context.Context, yad YaDisk, p Path) ([]string, error) {
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	r, e := GetResourceWithOptions(yad, string(p), &ResourceOptions{Fields: []string{"custom_properties"}})
+	if e != nil {
+		return nil, e
+	}
+	return r.Tags(), nil
+}
+
+// AddTags adds the tags to the resource at p and returns all its tags.
+//
+// Tags are read and written back, concurrent changes of the same resource may be lost.
+func AddTags(ctx context.Context, yad YaDisk, p Path, tags ...string) ([]string, error) {
+	return updateTags(ctx, yad, p, tags, true)
+}
+
+// RemoveTags removes the tags from the resource at p and returns the remaining tags.
+//
+// Tags are read and written back, concurrent changes of the same resource may be lost.
+func RemoveTags(ctx context.Context, yad YaDisk, p Path, tags ...string) ([]string, error) {
+	return updateTags(ctx, yad, p, tags, false)
+}
+
+// HasTags returns a predicate of FindByProperties matching resources with all the tags.
+func HasTags(tags ...string) func(r *Resource) bool {
+	return func(r *Resource) bool {
+		set := tagSet(r.Tags())
+		for _, t := range tags {
+			if !set[t] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func updateTags(ctx context.Context, yad YaDisk, p Path, tags []string, add bool) ([]string, error) {
+	for _, t := range tags {
+		if t == "" {
+			return nil, errors.New("yadisk: empty tag")
+		}
+	}
+	current, e := Tags(ctx, yad, p)
+	if e != nil {
+		return nil, e
+	}
+	set := tagSet(current)
+	for _, t := range tags {
+		if add {
+			set[t] = true
+		} else {
+			delete(set, t)
+		}
+	}
+	result := make([]string, 0, len(set))
+	for t := range set {
+		result = append(result, t)
+	}
+	sort.Strings(result)
+
+	patch := new(ResourcePatch)
+	if len(result) == 0 {
+		patch.Delete(TagsProperty)
+	} else {
+		patch.Set(TagsProperty, result)
+	}
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	if _, e := yad.UpdateResource(string(p), []string{"path"}, patch); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[t] = true
+	}
+	return set
+}
+
+// FindByProperties walks the tree below root and streams the resources matching the predicate.
+//
+// Only the fields needed to walk the tree and the custom properties are requested.
+// The error channel receives at most one error and is closed together with the resource channel.
+func FindByProperties(ctx context.Context, yad YaDisk, root Path, predicate func(r *Resource) bool) (<-chan *Resource, <-chan error) {
+	found := make(chan *Resource)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(found)
+		fields := embeddedFields("name", "path", "type", "size", "md5", "modified", "resource_id", "custom_properties")
+		e := walk(ctx, yad, root, fields, func(r *Resource) error {
+			if !predicate(r) {
+				return nil
+			}
+			select {
+			case found <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if e != nil {
+			errc <- e
+		}
+	}()
+	return found, errc
+}
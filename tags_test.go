@@ -0,0 +1,87 @@
+package yadisk_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+func TestAddTags(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/a.txt", []byte("a"))
+
+	tests := []struct {
+		name    string
+		call    func() ([]string, error)
+		want    []string
+		wantErr bool
+	}{
+		{"add", func() ([]string, error) { return yadisk.AddTags(ctx, d, "/a.txt", "b", "a") }, []string{"a", "b"}, false},
+		{"add_existing", func() ([]string, error) { return yadisk.AddTags(ctx, d, "/a.txt", "a", "c") }, []string{"a", "b", "c"}, false},
+		{"remove", func() ([]string, error) { return yadisk.RemoveTags(ctx, d, "/a.txt", "b", "x") }, []string{"a", "c"}, false},
+		{"tags", func() ([]string, error) { return yadisk.Tags(ctx, d, "/a.txt") }, []string{"a", "c"}, false},
+		{"unsorted", func() ([]string, error) {
+			patch := new(yadisk.ResourcePatch)
+			patch.Set(yadisk.TagsProperty, []string{"z", "c", "a"})
+			if _, err := d.UpdateResource("/a.txt", nil, patch); err != nil {
+				return nil, err
+			}
+			return yadisk.Tags(ctx, d, "/a.txt")
+		}, []string{"a", "c", "z"}, false},
+		{"remove_all", func() ([]string, error) { return yadisk.RemoveTags(ctx, d, "/a.txt", "a", "c", "z") }, []string{}, false},
+		{"empty_tag", func() ([]string, error) { return yadisk.AddTags(ctx, d, "/a.txt", "") }, nil, true},
+		{"missing", func() ([]string, error) { return yadisk.AddTags(ctx, d, "/missing", "a") }, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	r, _ := d.GetResource("/a.txt", nil, 0, 0, false, "", "")
+	if props, _ := r.CustomProperties.(map[string]interface{}); len(props) != 0 {
+		t.Errorf("custom properties = %v, want none", props)
+	}
+}
+
+func TestFindByProperties(t *testing.T) {
+	ctx := context.Background()
+	d := yadisktest.New(nil)
+	for _, p := range []string{"/a.txt", "/x/b.txt", "/x/y/c.txt", "/x/y/d.txt"} {
+		_ = d.WriteFile(p, []byte(p))
+	}
+	for _, p := range []yadisk.Path{"/a.txt", "/x/y", "/x/y/d.txt"} {
+		if _, err := yadisk.AddTags(ctx, d, p, "keep"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	found, errc := yadisk.FindByProperties(ctx, d, "/", yadisk.HasTags("keep"))
+	var got []string
+	for r := range found {
+		got = append(got, r.Path)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("FindByProperties() error = %v", err)
+	}
+	if want := []string{"disk:/a.txt", "disk:/x/y", "disk:/x/y/d.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindByProperties() = %v, want %v", got, want)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	found, errc = yadisk.FindByProperties(cancelled, d, "/", yadisk.HasTags("keep"))
+	for range found {
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("FindByProperties() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,74 @@
+package yadisk
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// openHref starts the download of a file by the link returned by the API.
+func openHref(ctx context.Context, client *http.Client, href string) (*http.Response, error) {
+	return openRange(ctx, client, href, 0, -1)
+}
+
+// openRange starts the download of n bytes of a file from off, or of the rest of the file if n is negative.
+func openRange(ctx context.Context, client *http.Client, href string, off int64, n int64) (*http.Response, error) {
+	req, e := http.NewRequest(http.MethodGet, href, nil)
+	if e != nil {
+		return nil, e
+	}
+	if off > 0 || n >= 0 {
+		r := "bytes=" + strconv.FormatInt(off, 10) + "-"
+		if n >= 0 {
+			r += strconv.FormatInt(off+n-1, 10)
+		}
+		req.Header.Set("Range", r)
+	}
+	resp, e := client.Do(req.WithContext(ctx))
+	if e != nil {
+		return nil, e
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range.
+		if _, e := io.CopyN(ioutil.Discard, resp.Body, off); e != nil {
+			_ = resp.Body.Close()
+			return nil, e
+		}
+	default:
+		_ = resp.Body.Close()
+		return nil, &Error{ErrorID: strconv.Itoa(resp.StatusCode), Description: resp.Status}
+	}
+	return resp, nil
+}
+
+// streamUpload uploads size bytes of body by the link, without buffering them.
+func streamUpload(ctx context.Context, client *http.Client, ur *ResourceUploadLink, body io.Reader, size int64) error {
+	method := ur.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+	req, e := http.NewRequest(method, ur.Href, ioutil.NopCloser(body))
+	if e != nil {
+		return e
+	}
+	req.ContentLength = size
+	resp, e := client.Do(req.WithContext(ctx))
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return new(PerformUpload).handleError(responseInfo{Status: resp.Status, StatusCode: resp.StatusCode})
+}
+
+// httpClient returns c, or http.DefaultClient if c is nil.
+func httpClient(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
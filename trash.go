@@ -3,8 +3,6 @@ package yadisk
 import (
 	"net/http"
 	"net/url"
-	"strconv"
-	"strings"
 )
 
 // Empty trash.
@@ -15,10 +13,20 @@ import (
 // If the path parameter is not specified or points to the root of the Recycle Bin,
 // the recycle bin will be completely cleared, otherwise only the resource pointed to by the path will be deleted from the Recycle Bin.
 func (yad *yandexDisk) ClearTrash(fields []string, forceAsync bool, path string) (l *Link, e error) {
+	return yad.ClearTrashWithOptions(&ClearTrashOptions{Fields: fields, ForceAsync: forceAsync, Path: path})
+}
+
+// Empty trash.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) ClearTrashWithOptions(opts *ClearTrashOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(ClearTrashOptions)
+	}
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("force_async", strconv.FormatBool(forceAsync))
-	values.Add("path", path)
+	addFields(values, opts.Fields)
+	addBool(values, "force_async", opts.ForceAsync)
+	addValue(values, "path", opts.Path)
 
 	req, e := yad.client.request(http.MethodDelete, "/disk/trash/resources?"+values.Encode(), nil)
 	if e != nil {
@@ -35,7 +43,28 @@ func (yad *yandexDisk) ClearTrash(fields []string, forceAsync bool, path string)
 
 // Get the contents of the Trash.
 func (yad *yandexDisk) GetTrashResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (r *TrashResource, e error) {
-	req, e := yad.getResource("trash/", path, fields, limit, offset, previewCrop, previewSize, sort)
+	return yad.trashResource(path, &ResourceOptions{
+		Fields:      fields,
+		Limit:       nonZero(limit),
+		Offset:      nonZero(offset),
+		PreviewCrop: previewCrop,
+		PreviewSize: PreviewSize(previewSize),
+		Sort:        SortField(sort),
+	})
+}
+
+// Get the contents of the Trash.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) GetTrashResourceWithOptions(path string, opts *ResourceOptions) (r *TrashResource, e error) {
+	if e := opts.validate(); e != nil {
+		return nil, e
+	}
+	return yad.trashResource(path, opts)
+}
+
+func (yad *yandexDisk) trashResource(path string, opts *ResourceOptions) (r *TrashResource, e error) {
+	req, e := yad.getResource("trash/", path, opts)
 	if e != nil {
 		return nil, e
 	}
@@ -53,12 +82,22 @@ func (yad *yandexDisk) GetTrashResource(path string, fields []string, limit int,
 // If recovery is asynchronous, it will return a response with code 202 and a link to the asynchronous operation.
 // Otherwise, it will return a response with code 201 and a link to the created resource.
 func (yad *yandexDisk) RestoreFromTrash(path string, fields []string, forceAsync bool, name string, overwrite bool) (l *Link, e error) {
+	return yad.RestoreFromTrashWithOptions(path, &RestoreFromTrashOptions{Fields: fields, ForceAsync: forceAsync, Name: name, Overwrite: overwrite})
+}
+
+// Recover Resource from Trash.
+//
+// Unset options are left out of the request.
+func (yad *yandexDisk) RestoreFromTrashWithOptions(path string, opts *RestoreFromTrashOptions) (l *Link, e error) {
+	if opts == nil {
+		opts = new(RestoreFromTrashOptions)
+	}
 	values := url.Values{}
-	values.Add("path", path)
-	values.Add("fields", strings.Join(fields, ","))
-	values.Add("force_async", strconv.FormatBool(forceAsync))
-	values.Add("name", name)
-	values.Add("overwrite", strconv.FormatBool(overwrite))
+	addValue(values, "path", path)
+	addFields(values, opts.Fields)
+	addBool(values, "force_async", opts.ForceAsync)
+	addValue(values, "name", opts.Name)
+	addBool(values, "overwrite", opts.Overwrite)
 
 	req, e := yad.client.request(http.MethodPut, "/disk/trash/resources/restore?"+values.Encode(), nil)
 	if e != nil {
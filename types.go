@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type YaDisk interface {
@@ -126,6 +127,78 @@ type YaDisk interface {
 	PerformPartialUpload(ur *ResourceUploadLink, data *bytes.Buffer, partSize int64) (pu *PerformUpload, e error)
 }
 
+// YaDiskWithOptions is a YaDisk taking the request parameters as option structs, where unset options are left
+// out of the request. The package functions of the same names fall back to the positional methods for a YaDisk
+// not implementing it.
+type YaDiskWithOptions interface {
+	YaDisk
+
+	// Get the contents of the Trash.
+	//
+	// Unset options are left out of the request.
+	GetTrashResourceWithOptions(path string, opts *ResourceOptions) (r *TrashResource, e error)
+
+	// Get meta information about a file or directory.
+	//
+	// Unset options are left out of the request.
+	GetResourceWithOptions(path string, opts *ResourceOptions) (r *Resource, e error)
+
+	// Get file list sorted by name.
+	//
+	// Unset options are left out of the request.
+	GetFlatFilesListWithOptions(opts *FilesListOptions) (l *FilesResourceList, e error)
+
+	// Get a list of files ordered by download date.
+	//
+	// Unset options are left out of the request.
+	GetLastUploadedFilesListWithOptions(opts *LastUploadedOptions) (l *LastUploadedResourceList, e error)
+
+	// Get a list of published resources.
+	//
+	// Unset options are left out of the request.
+	ListPublicResourcesWithOptions(opts *PublicResourcesListOptions) (l *PublicResourcesList, e error)
+
+	// Get meta-information about a public file or directory.
+	//
+	// Unset options are left out of the request.
+	GetPublicResourceWithOptions(publicKey string, opts *PublicResourceOptions) (r *PublicResource, e error)
+
+	// Empty trash.
+	//
+	// Unset options are left out of the request.
+	ClearTrashWithOptions(opts *ClearTrashOptions) (l *Link, e error)
+
+	// Recover Resource from Trash.
+	//
+	// Unset options are left out of the request.
+	RestoreFromTrashWithOptions(path string, opts *RestoreFromTrashOptions) (l *Link, e error)
+
+	// Delete file or folder.
+	//
+	// Unset options are left out of the request.
+	DeleteResourceWithOptions(path string, opts *DeleteOptions) (l *Link, e error)
+
+	// Create a copy of the file or folder.
+	//
+	// Unset options are left out of the request.
+	CopyResourceWithOptions(from string, path string, opts *TransferOptions) (l *Link, e error)
+
+	// Move a file or folder.
+	//
+	// Unset options are left out of the request.
+	MoveResourceWithOptions(from string, path string, opts *TransferOptions) (l *Link, e error)
+
+	// Upload file to Disk by URL.
+	//
+	// Unset options are left out of the request.
+	UploadExternalResourceWithOptions(path string, externalURL string, opts *UploadExternalOptions) (l *Link, e error)
+
+	// Save the public resource to the Downloads folder.
+	//
+	// Unset options are left out of the request.
+	SaveToDiskPublicResourceWithOptions(publicKey string, opts *SaveToDiskOptions) (l *Link, e error)
+}
+
 type performPartialUploadResult struct {
 	out *PerformUpload
 	err error
@@ -136,6 +209,8 @@ type yandexDisk struct {
 	client *client
 }
 
+var _ YaDiskWithOptions = (*yandexDisk)(nil)
+
 // Token for access to Yandex.Disk Rest-API
 type Token struct {
 	AccessToken string
@@ -189,12 +264,12 @@ type DeleteResource struct {
 }
 
 type Disk struct {
-	MaxFileSize                int  `json:"max_file_size"`
-	UnlimitedAutouploadEnabled bool `json:"unlimited_autoupload_enabled"`
-	TotalSpace                 int  `json:"total_space"`
-	TrashSize                  int  `json:"trash_size"`
-	IsPaid                     bool `json:"is_paid"`
-	UsedSpace                  int  `json:"used_space"`
+	MaxFileSize                int64 `json:"max_file_size"`
+	UnlimitedAutouploadEnabled bool  `json:"unlimited_autoupload_enabled"`
+	TotalSpace                 int64 `json:"total_space"`
+	TrashSize                  int64 `json:"trash_size"`
+	IsPaid                     bool  `json:"is_paid"`
+	UsedSpace                  int64 `json:"used_space"`
 	SystemFolders              struct {
 		Odnoklassniki string `json:"odnoklassniki"`
 		Google        string `json:"google"`
@@ -208,31 +283,31 @@ type Disk struct {
 		Screenshots   string `json:"screenshots"`
 		Photostream   string `json:"photostream"`
 	} `json:"system_folders"`
-	User     User `json:"user"`
-	Revision int  `json:"revision"`
+	User     User  `json:"user"`
+	Revision int64 `json:"revision"`
 }
 
 type baseResource struct {
-	ResourceID     string     `json:"resource_id"`
-	Share          Share      `json:"share"`
-	File           string     `json:"file"`
-	Size           int        `json:"size"`
-	PhotosliceTime string     `json:"photoslice_time"`
-	Exif           Exif       `json:"exif"`
-	MediaType      string     `json:"media_type"`
-	Sha256         string     `json:"sha256"`
-	Type           string     `json:"type"`
-	MimeType       string     `json:"mime_type"`
-	Revision       int        `json:"revision"`
-	PublicURL      string     `json:"public_url"`
-	Path           string     `json:"path"`
-	Md5            string     `json:"md5"`
-	PublicKey      string     `json:"public_key"`
-	Preview        string     `json:"preview"`
-	Name           string     `json:"name"`
-	Created        string     `json:"created"`
-	Modified       string     `json:"modified"`
-	CommentIds     CommentIds `json:"comment_ids"`
+	ResourceID     string       `json:"resource_id"`
+	Share          Share        `json:"share"`
+	File           string       `json:"file"`
+	Size           int64        `json:"size"`
+	PhotosliceTime Time         `json:"photoslice_time"`
+	Exif           Exif         `json:"exif"`
+	MediaType      MediaType    `json:"media_type"`
+	Sha256         string       `json:"sha256"`
+	Type           ResourceType `json:"type"`
+	MimeType       string       `json:"mime_type"`
+	Revision       int64        `json:"revision"`
+	PublicURL      string       `json:"public_url"`
+	Path           string       `json:"path"`
+	Md5            string       `json:"md5"`
+	PublicKey      string       `json:"public_key"`
+	Preview        string       `json:"preview"`
+	Name           string       `json:"name"`
+	Created        Time         `json:"created"`
+	Modified       Time         `json:"modified"`
+	CommentIds     CommentIds   `json:"comment_ids"`
 }
 
 type Link struct {
@@ -266,10 +341,10 @@ type LastUploadedResourceList struct {
 }
 
 type PublicResourcesList struct {
-	Items  []Resource `json:"items"`
-	Type   string     `json:"type"`
-	Limit  int        `json:"limit"`
-	Offset int        `json:"offset"`
+	Items  []Resource   `json:"items"`
+	Type   ResourceType `json:"type"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
 }
 
 type PublicResource struct {
@@ -284,7 +359,7 @@ type TrashResource struct {
 	Embedded         TrashEmbedded    `json:"_embedded"`
 	CustomProperties customProperties `json:"custom_properties"`
 	OriginPath       string           `json:"origin_path"`
-	Deleted          string           `json:"deleted"`
+	Deleted          Time             `json:"deleted"`
 }
 
 type OperationStatus struct {
@@ -305,11 +380,11 @@ type Owner struct {
 }
 
 type baseEmbedded struct {
-	Sort   string `json:"sort"`
-	Limit  int    `json:"limit"`
-	Offset int    `json:"offset"`
-	Path   string `json:"path"`
-	Total  int    `json:"total"`
+	Sort   SortField `json:"sort"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+	Path   string    `json:"path"`
+	Total  int       `json:"total"`
 }
 
 type TrashEmbedded struct {
@@ -334,7 +409,38 @@ type Share struct {
 }
 
 type Exif struct {
-	DateTime string `json:"date_time"`
+	DateTime     Time     `json:"date_time"`
+	GPSLatitude  *float64 `json:"gps_latitude,omitempty"`
+	GPSLongitude *float64 `json:"gps_longitude,omitempty"`
+}
+
+// Location returns the GPS coordinates of the photo, ok is false if the file has none.
+func (e Exif) Location() (latitude float64, longitude float64, ok bool) {
+	if e.GPSLatitude == nil || e.GPSLongitude == nil {
+		return 0, 0, false
+	}
+	return *e.GPSLatitude, *e.GPSLongitude, true
+}
+
+// Time is a timestamp of the model. It is encoded as "" when zero, as the timestamps were strings before,
+// and "" decodes to the zero time.
+type Time struct {
+	time.Time
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if s := string(data); s == `""` || s == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	return t.Time.UnmarshalJSON(data)
 }
 
 type CommentIds struct {
@@ -1,6 +1,10 @@
 package yadisk
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
 
 func Test_responseInfo_setResponseInfo(t *testing.T) {
 	type args struct {
@@ -61,3 +65,72 @@ func TestPerformUpload_handleError(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_UnmarshalJSON(t *testing.T) {
+	created := time.Date(2020, 5, 1, 10, 0, 0, 0, time.FixedZone("", 3*60*60))
+	tests := []struct {
+		name     string
+		data     string
+		want     *Resource
+		wantErr  bool
+		wantExif bool
+	}{
+		{"api", `{"created":"2020-05-01T10:00:00+03:00","size":5368709120,"exif":{"date_time":"2020-05-01T10:00:00+03:00","gps_latitude":55.75,"gps_longitude":37.62}}`,
+			&Resource{baseResource: baseResource{Created: Time{created}, Size: 5 << 30, Exif: Exif{DateTime: Time{created}}}}, false, true},
+		{"old_empty_times", `{"created":"","modified": "","photoslice_time":"","exif":{"date_time":""},"_embedded":{"items":[{"created":""}]}}`,
+			&Resource{Embedded: Embedded{Items: []Resource{{}}}}, false, false},
+		{"bad_time", `{"created":"yesterday"}`, nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := new(Resource)
+			err := json.Unmarshal([]byte(tt.data), r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resource.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !r.Created.Equal(tt.want.Created.Time) || !r.Exif.DateTime.Equal(tt.want.Exif.DateTime.Time) || r.Size != tt.want.Size || len(r.Embedded.Items) != len(tt.want.Embedded.Items) {
+				t.Errorf("Resource.UnmarshalJSON() = %+v, want %+v", r, tt.want)
+			}
+			if lat, lon, ok := r.Exif.Location(); ok != tt.wantExif || (ok && (lat != 55.75 || lon != 37.62)) {
+				t.Errorf("Exif.Location() = %v, %v, %v", lat, lon, ok)
+			}
+		})
+	}
+}
+
+func TestTrashResource_UnmarshalJSON(t *testing.T) {
+	r := new(TrashResource)
+	if err := json.Unmarshal([]byte(`{"deleted":"","origin_path":"disk:/a"}`), r); err != nil || !r.Deleted.IsZero() || r.OriginPath != "disk:/a" {
+		t.Errorf("TrashResource.UnmarshalJSON() = %+v, error = %v", r, err)
+	}
+}
+
+func TestTime_JSON(t *testing.T) {
+	r := new(Resource)
+	data := `{"created":"","custom_properties":{"created":"","tag":"a"}}`
+	if err := json.Unmarshal([]byte(data), r); err != nil {
+		t.Fatal(err)
+	}
+	if props := r.CustomProperties.(map[string]interface{}); props["created"] != "" {
+		t.Errorf("custom_properties = %v, want the user data unchanged", props)
+	}
+	tests := []struct {
+		name string
+		t    Time
+		want string
+	}{
+		{"zero", Time{}, `""`},
+		{"set", Time{time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)}, `"2020-05-01T10:00:00Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.t)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Time.MarshalJSON() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
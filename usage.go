@@ -0,0 +1,309 @@
+package yadisk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Number of folders listed by AnalyzeUsage between the saves of the state file.
+const usageSaveEvery = 100
+
+// Options of AnalyzeUsage.
+type UsageOptions struct {
+	// File keeping the progress of the scan, if set. An interrupted scan of the same root resumes from it,
+	// the file is removed once the scan is complete.
+	StateFile string
+}
+
+// Usage is the space taken by files.
+type Usage struct {
+	Size  int64 `json:"size"`
+	Files int   `json:"files"`
+}
+
+// FolderUsage is the space taken by the files in a folder and its subfolders.
+type FolderUsage struct {
+	Path Path `json:"path"`
+	Usage
+}
+
+// MediaUsage is the space taken by the files of a media type.
+type MediaUsage struct {
+	MediaType MediaType `json:"media_type"`
+	Usage
+}
+
+// DiskUsage is the result of AnalyzeUsage.
+type DiskUsage struct {
+	Root Path `json:"root"`
+	// Folders left to list, the scan is complete if empty.
+	Pending []Path `json:"pending,omitempty"`
+	// Files directly in each listed folder.
+	Own map[Path]*Usage `json:"own"`
+	// Files of each media type.
+	Media map[MediaType]*Usage `json:"media"`
+}
+
+// AnalyzeUsage lists the tree below root and sums up the sizes and the numbers of files per folder and per media type.
+//
+// Folders deleted during the scan are left out. On an error the partial usage is returned together with it and, if StateFile is set, saved there for a later call.
+func AnalyzeUsage(ctx context.Context, yad YaDisk, root Path, opts *UsageOptions) (*DiskUsage, error) {
+	o := UsageOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	root, e := ParsePath(string(root))
+	if e != nil {
+		return nil, e
+	}
+	u, e := loadUsage(o.StateFile, root)
+	if e != nil {
+		return nil, e
+	}
+	if u == nil {
+		u = &DiskUsage{Root: root, Pending: []Path{root}, Own: make(map[Path]*Usage), Media: make(map[MediaType]*Usage)}
+	}
+	for listed := 1; len(u.Pending) > 0; listed++ {
+		e := u.list(ctx, yad, u.Pending[0])
+		if IsErrorID(e, ErrorIDNotFound) && !u.Pending[0].Equal(u.Root) {
+			// Deleted since its parent was listed.
+			u.Pending = u.Pending[1:]
+			e = nil
+		}
+		if e != nil {
+			if se := saveUsage(o.StateFile, u); se != nil {
+				return u, fmt.Errorf("%v, saving the state: %v", e, se)
+			}
+			return u, e
+		}
+		if listed%usageSaveEvery == 0 {
+			if e := saveUsage(o.StateFile, u); e != nil {
+				return u, e
+			}
+		}
+	}
+	if o.StateFile != "" {
+		if e := os.Remove(o.StateFile); e != nil && !os.IsNotExist(e) {
+			return u, e
+		}
+	}
+	return u, nil
+}
+
+// list adds the files of the first pending folder and queues its subfolders. Nothing is added if it fails,
+// so the folder can be listed again.
+func (u *DiskUsage) list(ctx context.Context, yad YaDisk, dir Path) error {
+	own := new(Usage)
+	media := make(map[MediaType]*Usage)
+	var subdirs []Path
+	fields := embeddedFields("path", "type", "size", "media_type")
+	for offset := 0; ; offset += walkLimit {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		r, e := GetResourceWithOptions(yad, string(dir), &ResourceOptions{Fields: fields, Limit: Int(walkLimit), Offset: Int(offset)})
+		if e != nil {
+			return e
+		}
+		for _, item := range r.Embedded.Items {
+			if item.Type == ResourceTypeDir {
+				subdirs = append(subdirs, Path(item.Path))
+				continue
+			}
+			own.add(item.Size)
+			mt := item.MediaType
+			if mt == "" {
+				mt = MediaTypeUnknown
+			}
+			if media[mt] == nil {
+				media[mt] = new(Usage)
+			}
+			media[mt].add(item.Size)
+		}
+		if len(r.Embedded.Items) < walkLimit || offset+walkLimit >= r.Embedded.Total {
+			break
+		}
+	}
+	u.Own[dir] = own
+	for mt, m := range media {
+		if u.Media[mt] == nil {
+			u.Media[mt] = new(Usage)
+		}
+		u.Media[mt].Size += m.Size
+		u.Media[mt].Files += m.Files
+	}
+	u.Pending = append(u.Pending[1:], subdirs...)
+	return nil
+}
+
+func (u *Usage) add(size int64) {
+	u.Size += size
+	u.Files++
+}
+
+// Complete reports whether all the folders were listed.
+func (u *DiskUsage) Complete() bool {
+	return len(u.Pending) == 0
+}
+
+// Total returns the usage of the whole tree.
+func (u *DiskUsage) Total() Usage {
+	var total Usage
+	for _, own := range u.Own {
+		total.Size += own.Size
+		total.Files += own.Files
+	}
+	return total
+}
+
+// Folders returns the usage of each listed folder including its subfolders, largest first.
+func (u *DiskUsage) Folders() []FolderUsage {
+	totals := make(map[Path]*Usage, len(u.Own))
+	for dir, own := range u.Own {
+		for p := dir; ; p = p.Dir() {
+			t := totals[p]
+			if t == nil {
+				t = new(Usage)
+				totals[p] = t
+			}
+			t.Size += own.Size
+			t.Files += own.Files
+			if p.Equal(u.Root) || p.IsRoot() {
+				break
+			}
+		}
+	}
+	folders := make([]FolderUsage, 0, len(totals))
+	for p, t := range totals {
+		folders = append(folders, FolderUsage{Path: p, Usage: *t})
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		if folders[i].Size != folders[j].Size {
+			return folders[i].Size > folders[j].Size
+		}
+		return folders[i].Path < folders[j].Path
+	})
+	return folders
+}
+
+// MediaTypes returns the usage of each media type, largest first.
+func (u *DiskUsage) MediaTypes() []MediaUsage {
+	media := make([]MediaUsage, 0, len(u.Media))
+	for mt, m := range u.Media {
+		media = append(media, MediaUsage{MediaType: mt, Usage: *m})
+	}
+	sort.Slice(media, func(i, j int) bool {
+		if media[i].Size != media[j].Size {
+			return media[i].Size > media[j].Size
+		}
+		return media[i].MediaType < media[j].MediaType
+	})
+	return media
+}
+
+// Top returns the n largest folders below the root, all of them if n is not positive.
+func (u *DiskUsage) Top(n int) []FolderUsage {
+	var top []FolderUsage
+	for _, f := range u.Folders() {
+		if f.Path.Equal(u.Root) {
+			continue
+		}
+		if n > 0 && len(top) == n {
+			break
+		}
+		top = append(top, f)
+	}
+	return top
+}
+
+// PrintTop writes a table of the n largest folders and of the media types.
+func (u *DiskUsage) PrintTop(w io.Writer, n int) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	total := u.Total()
+	fmt.Fprintf(tw, "%d\t%d\t%s\t\n", total.Size, total.Files, u.Root)
+	for _, f := range u.Top(n) {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t\n", f.Size, f.Files, f.Path)
+	}
+	fmt.Fprintln(tw)
+	for _, m := range u.MediaTypes() {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t\n", m.Size, m.Files, m.MediaType)
+	}
+	if !u.Complete() {
+		fmt.Fprintf(tw, "\n%d folders not listed yet\n", len(u.Pending))
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the usage of the folders and of the media types as a JSON object.
+func (u *DiskUsage) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Root       Path          `json:"root"`
+		Complete   bool          `json:"complete"`
+		Total      Usage         `json:"total"`
+		Folders    []FolderUsage `json:"folders"`
+		MediaTypes []MediaUsage  `json:"media_types"`
+	}{u.Root, u.Complete(), u.Total(), u.Folders(), u.MediaTypes()})
+}
+
+// WriteCSV writes the usage as CSV with the columns kind, name, size and files,
+// where kind is "folder" or "media_type".
+func (u *DiskUsage) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"kind", "name", "size", "files"})
+	for _, f := range u.Folders() {
+		_ = cw.Write([]string{"folder", string(f.Path), strconv.FormatInt(f.Size, 10), strconv.Itoa(f.Files)})
+	}
+	for _, m := range u.MediaTypes() {
+		_ = cw.Write([]string{"media_type", string(m.MediaType), strconv.FormatInt(m.Size, 10), strconv.Itoa(m.Files)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// loadUsage returns the unfinished scan of root saved in the file, nil if there is none.
+func loadUsage(file string, root Path) (*DiskUsage, error) {
+	if file == "" {
+		return nil, nil
+	}
+	f, e := os.Open(file)
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+	u := new(DiskUsage)
+	if e := json.NewDecoder(f).Decode(u); e != nil {
+		return nil, fmt.Errorf("yadisk: usage state %s: %v", file, e)
+	}
+	if !u.Root.Equal(root) || u.Complete() {
+		return nil, nil
+	}
+	if u.Own == nil {
+		u.Own = make(map[Path]*Usage)
+	}
+	if u.Media == nil {
+		u.Media = make(map[MediaType]*Usage)
+	}
+	return u, nil
+}
+
+// saveUsage replaces the file with the state of the scan.
+func saveUsage(file string, u *DiskUsage) error {
+	if file == "" {
+		return nil
+	}
+	return writeFileAtomic(file, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(u)
+	})
+}
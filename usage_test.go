@@ -0,0 +1,179 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// failingDisk fails the listing of a folder while fail is set and records the listed folders.
+type failingDisk struct {
+	*yadisktest.Disk
+	fail   yadisk.Path
+	listed []yadisk.Path
+}
+
+func (f *failingDisk) GetResourceWithOptions(path string, opts *yadisk.ResourceOptions) (*yadisk.Resource, error) {
+	if f.fail != "" && yadisk.Path(path).Equal(f.fail) {
+		return nil, errors.New("listing failed")
+	}
+	f.listed = append(f.listed, yadisk.Path(path).Clean())
+	return f.Disk.GetResourceWithOptions(path, opts)
+}
+
+func newUsageDisk(t *testing.T) *yadisktest.Disk {
+	d := yadisktest.New(nil)
+	files := map[string]int{
+		"/u/photos/2020/a.jpg": 100,
+		"/u/photos/2020/b.jpg": 200,
+		"/u/photos/c.jpg":      50,
+		"/u/docs/report.pdf":   30,
+		"/u/docs/notes.txt":    5,
+		"/u/readme.txt":        1,
+		"/other/big.zip":       1000,
+	}
+	for p, n := range files {
+		if err := d.WriteFile(p, bytes.Repeat([]byte("x"), n)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.CreateResource("/u/empty", nil); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func folderLines(folders []yadisk.FolderUsage) []string {
+	var lines []string
+	for _, f := range folders {
+		lines = append(lines, fmt.Sprintf("%s %d %d", f.Path, f.Size, f.Files))
+	}
+	return lines
+}
+
+func TestAnalyzeUsage(t *testing.T) {
+	u, err := yadisk.AnalyzeUsage(context.Background(), newUsageDisk(t), "/u", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := u.Total(); !u.Complete() || total.Size != 386 || total.Files != 6 {
+		t.Errorf("AnalyzeUsage() total = %+v, complete = %v", total, u.Complete())
+	}
+	want := []string{
+		"disk:/u 386 6",
+		"disk:/u/photos 350 3",
+		"disk:/u/photos/2020 300 2",
+		"disk:/u/docs 35 2",
+		"disk:/u/empty 0 0",
+	}
+	if got := folderLines(u.Folders()); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Folders() = %q, want %q", got, want)
+	}
+	if got := folderLines(u.Top(2)); fmt.Sprint(got) != fmt.Sprint(want[1:3]) {
+		t.Errorf("Top(2) = %q, want %q", got, want[1:3])
+	}
+	var media []string
+	for _, m := range u.MediaTypes() {
+		media = append(media, fmt.Sprintf("%s %d %d", m.MediaType, m.Size, m.Files))
+	}
+	if len(media) == 0 || media[0] != "image 350 3" {
+		t.Errorf("MediaTypes() = %q", media)
+	}
+
+	var buf bytes.Buffer
+	if err := u.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "kind,name,size,files" || lines[1] != "folder,disk:/u,386,6" || !strings.Contains(buf.String(), "media_type,image,350,3") {
+		t.Errorf("WriteCSV() = %s", buf.String())
+	}
+	buf.Reset()
+	if err := u.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"complete": true`, `"path": "disk:/u/photos"`, `"media_type": "image"`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("WriteJSON() = %s, want %s in it", buf.String(), s)
+		}
+	}
+	buf.Reset()
+	if err := u.PrintTop(&buf, 1); err != nil || !strings.Contains(buf.String(), "disk:/u/photos") || strings.Contains(buf.String(), "2020") {
+		t.Errorf("PrintTop() = %s, error = %v", buf.String(), err)
+	}
+}
+
+func TestAnalyzeUsage_resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yadisk-usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	state := filepath.Join(dir, "usage.json")
+	opts := &yadisk.UsageOptions{StateFile: state}
+	ctx := context.Background()
+
+	d := &failingDisk{Disk: newUsageDisk(t), fail: "disk:/u/photos/2020"}
+	u, err := yadisk.AnalyzeUsage(ctx, d, "/u", opts)
+	if err == nil || u.Complete() {
+		t.Fatalf("AnalyzeUsage() complete = %v, error = %v", u.Complete(), err)
+	}
+	if _, err := os.Stat(state); err != nil {
+		t.Fatalf("state file: %v", err)
+	}
+
+	d.fail, d.listed = "", nil
+	u, err = yadisk.AnalyzeUsage(ctx, d, "/u", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(d.listed) != "[disk:/u/photos/2020]" {
+		t.Errorf("folders listed after resuming = %v", d.listed)
+	}
+	if total := u.Total(); total.Size != 386 || total.Files != 6 {
+		t.Errorf("AnalyzeUsage() after resuming total = %+v", total)
+	}
+	if _, err := os.Stat(state); !os.IsNotExist(err) {
+		t.Errorf("state file after a complete scan: %v", err)
+	}
+}
+
+func TestAnalyzeUsage_deleted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yadisk-usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := &yadisk.UsageOptions{StateFile: filepath.Join(dir, "usage.json")}
+	ctx := context.Background()
+
+	d := &failingDisk{Disk: newUsageDisk(t), fail: "disk:/u/photos/2020"}
+	if _, err := yadisk.AnalyzeUsage(ctx, d, "/u", opts); err == nil {
+		t.Fatal("AnalyzeUsage() error = nil")
+	}
+	// The pending folder is gone when the scan resumes.
+	if _, err := d.DeleteResource("/u/photos/2020", nil, false, "", true); err != nil {
+		t.Fatal(err)
+	}
+	d.fail = ""
+	u, err := yadisk.AnalyzeUsage(ctx, d, "/u", opts)
+	if err != nil || !u.Complete() {
+		t.Fatalf("AnalyzeUsage() complete = %v, error = %v", u.Complete(), err)
+	}
+	if total := u.Total(); total.Size != 86 || total.Files != 4 {
+		t.Errorf("AnalyzeUsage() total = %+v, want 86 bytes in 4 files", total)
+	}
+
+	if _, err := yadisk.AnalyzeUsage(ctx, d, "/missing", nil); !yadisk.IsErrorID(err, yadisk.ErrorIDNotFound) {
+		t.Errorf("AnalyzeUsage() of a missing root error = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package yadisk
+
+import (
+	"context"
+)
+
+// Page size of the listings requested while walking a tree.
+const walkLimit = 100
+
+// walk calls fn for each resource below root, folders before their contents.
+// Folders deleted while walking are skipped, except the root.
+// Only the fields are requested, they must include the type and the path of the items.
+func walk(ctx context.Context, yad YaDisk, root Path, fields []string, fn func(r *Resource) error) error {
+	queue := []Path{root}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		for offset := 0; ; offset += walkLimit {
+			if e := ctx.Err(); e != nil {
+				return e
+			}
+			r, e := GetResourceWithOptions(yad, string(dir), &ResourceOptions{Fields: fields, Limit: Int(walkLimit), Offset: Int(offset)})
+			if e != nil && dir != root && IsErrorID(e, ErrorIDNotFound) {
+				// Deleted after its parent was listed.
+				break
+			}
+			if e != nil {
+				return e
+			}
+			for i := range r.Embedded.Items {
+				item := &r.Embedded.Items[i]
+				if e := fn(item); e != nil {
+					return e
+				}
+				if item.Type == ResourceTypeDir {
+					queue = append(queue, Path(item.Path))
+				}
+			}
+			if len(r.Embedded.Items) < walkLimit || offset+walkLimit >= r.Embedded.Total {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// embeddedFields returns the fields of the items of a listing and the fields needed to page through it.
+func embeddedFields(fields ...string) []string {
+	out := []string{"_embedded.limit", "_embedded.offset", "_embedded.total"}
+	for _, f := range fields {
+		out = append(out, "_embedded.items."+f)
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Interval between the revision checks of Watch when none is given.
+const DefaultWatchInterval = 30 * time.Second
+
+// Options of Watch.
+type WatchOptions struct {
+	// Interval between the checks of the Disk revision, DefaultWatchInterval by default.
+	Interval time.Duration
+	// File keeping the last snapshot, if set. A restarted watcher reports the changes made since it was saved.
+	StateFile string
+}
+
+// Watch polls the revision of the Disk and, when it changes, streams the changes of the tree below root.
+//
+// The first snapshot is read from StateFile if it holds one of root, the tree is listed otherwise and no events are
+// sent for it. Each snapshot is saved to StateFile once its events were received. The watcher stops on the first
+// error or when ctx is done, the error channel receives at most one error and is closed together with the event channel.
+func Watch(ctx context.Context, yad YaDisk, root Path, opts *WatchOptions) (<-chan Event, <-chan error) {
+	o := WatchOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultWatchInterval
+	}
+	events := make(chan Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(events)
+		if e := watch(ctx, yad, root, &o, events); e != nil && e != ctx.Err() {
+			errc <- e
+		}
+	}()
+	return events, errc
+}
+
+func watch(ctx context.Context, yad YaDisk, root Path, o *WatchOptions, events chan<- Event) error {
+	root, e := ParsePath(string(root))
+	if e != nil {
+		return e
+	}
+	last, e := loadSnapshot(o.StateFile, root)
+	if e != nil {
+		return e
+	}
+	if last == nil {
+		if last, e = TakeSnapshot(ctx, yad, root); e != nil {
+			return e
+		}
+		if e := saveSnapshot(o.StateFile, last); e != nil {
+			return e
+		}
+	}
+	for {
+		disk, e := yad.GetDisk([]string{"revision"})
+		if e != nil {
+			return e
+		}
+		if disk.Revision != last.Revision {
+			next, e := TakeSnapshot(ctx, yad, root)
+			if e != nil {
+				return e
+			}
+			for _, ev := range DiffSnapshots(last, next) {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if e := saveSnapshot(o.StateFile, next); e != nil {
+				return e
+			}
+			last = next
+		}
+		select {
+		case <-time.After(o.Interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// loadSnapshot returns the snapshot of root saved in the file, nil if there is none.
+// Files saved before the JSON Lines format hold the snapshot as a single JSON object, without a version.
+func loadSnapshot(file string, root Path) (*Snapshot, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, e := ioutil.ReadFile(file)
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if e := json.NewDecoder(bytes.NewReader(data)).Decode(&header); e != nil {
+		return nil, fmt.Errorf("yadisk: snapshot %s: %v", file, e)
+	}
+	var s *Snapshot
+	if header.Version == 0 {
+		s = new(Snapshot)
+		if e := json.Unmarshal(data, s); e != nil {
+			return nil, fmt.Errorf("yadisk: snapshot %s: %v", file, e)
+		}
+		if s.Entries == nil {
+			s.Entries = make(map[Path]*SnapshotEntry)
+		}
+	} else if s, e = ReadSnapshot(bytes.NewReader(data)); e != nil {
+		return nil, fmt.Errorf("%v in %s", e, file)
+	}
+	if !s.Root.Equal(root) {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// saveSnapshot replaces the file with the snapshot.
+func saveSnapshot(file string, s *Snapshot) error {
+	if file == "" {
+		return nil
+	}
+	return writeFileAtomic(file, func(w io.Writer) error {
+		_, e := s.WriteTo(w)
+		return e
+	})
+}
+
+// writeFileAtomic replaces the file with the output of write, through a temporary file so a crash keeps the previous one.
+func writeFileAtomic(file string, write func(w io.Writer) error) error {
+	tmp, e := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if e != nil {
+		return e
+	}
+	defer os.Remove(tmp.Name())
+	e = write(tmp)
+	if ce := tmp.Close(); e == nil {
+		e = ce
+	}
+	if e != nil {
+		return e
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,209 @@
+package yadisk_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+	"github.com/nikitaksv/yandex-disk-sdk-go/yadisktest"
+)
+
+// collect receives n events, or fails after a timeout.
+func collect(t *testing.T, events <-chan yadisk.Event, errc <-chan error, n int) []string {
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < n {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("Watch() stopped: %v", <-errc)
+			}
+			s := string(ev.Type) + " " + string(ev.Path)
+			if ev.OldPath != "" {
+				s += " from " + string(ev.OldPath)
+			}
+			got = append(got, s)
+		case <-timeout:
+			t.Fatalf("Watch() events = %v, want %d", got, n)
+		}
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yadisk-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	state := filepath.Join(dir, "state.jsonl")
+
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/w/a.txt", []byte("a"))
+	_ = d.WriteFile("/other/x.txt", []byte("x"))
+	opts := &yadisk.WatchOptions{Interval: time.Millisecond, StateFile: state}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errc := yadisk.Watch(ctx, d, "/w", opts)
+	for {
+		if _, err := os.Stat(state); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_ = d.WriteFile("/w/new.txt", []byte("new"))
+	_ = d.WriteFile("/other/y.txt", []byte("y"))
+	if _, err := d.MoveResource("/w/a.txt", "/w/b.txt", nil, false, false); err != nil {
+		t.Fatal(err)
+	}
+	got := collect(t, events, errc, 2)
+	want := []string{"created disk:/w/new.txt", "moved disk:/w/b.txt from disk:/w/a.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Watch() events = %v, want %v", got, want)
+	}
+	cancel()
+	for range events {
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+
+	// Changes made while the watcher was stopped are reported after a restart.
+	if _, err := d.DeleteResource("/w/new.txt", nil, false, "", false); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, errc = yadisk.Watch(ctx, d, "/w", opts)
+	if got := collect(t, events, errc, 1); got[0] != "deleted disk:/w/new.txt" {
+		t.Errorf("Watch() after restart events = %v", got)
+	}
+}
+
+func TestWatch_legacyState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yadisk-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// State file written before snapshots were saved as JSON Lines.
+	state := filepath.Join(dir, "state.json")
+	legacy := `{"root":"disk:/w","revision":0,"taken":"2024-01-01T00:00:00Z","entries":{"disk:/w/gone.txt":` +
+		`{"path":"disk:/w/gone.txt","resource_id":"1","type":"file","size":1,"md5":"x","modified":"2024-01-01T00:00:00Z"}}}`
+	if err := ioutil.WriteFile(state, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/w/a.txt", []byte("a"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errc := yadisk.Watch(ctx, d, "/w", &yadisk.WatchOptions{Interval: time.Millisecond, StateFile: state})
+	got := collect(t, events, errc, 2)
+	want := []string{"created disk:/w/a.txt", "deleted disk:/w/gone.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Watch() events = %v, want %v", got, want)
+	}
+}
+
+// deletingDisk deletes a folder right after the listing of its parent, on the nth listing of the parent.
+type deletingDisk struct {
+	*yadisktest.Disk
+	parent, dir yadisk.Path
+	n           int
+}
+
+func (d *deletingDisk) GetResourceWithOptions(path string, opts *yadisk.ResourceOptions) (*yadisk.Resource, error) {
+	r, err := d.Disk.GetResourceWithOptions(path, opts)
+	if err == nil && yadisk.Path(path).Equal(d.parent) {
+		if d.n--; d.n == 0 {
+			_, _ = d.Disk.DeleteResource(string(d.dir), nil, false, "", true)
+		}
+	}
+	return r, err
+}
+
+func TestWatch_deletedDuringListing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yadisk-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	state := filepath.Join(dir, "state.jsonl")
+
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/w/a.txt", []byte("a"))
+	_ = d.WriteFile("/w/sub/b.txt", []byte("b"))
+	yad := &deletingDisk{Disk: d, parent: "/w", dir: "/w/sub", n: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errc := yadisk.Watch(ctx, yad, "/w", &yadisk.WatchOptions{Interval: time.Millisecond, StateFile: state})
+	for {
+		if _, err := os.Stat(state); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// The next snapshot lists /w, then finds /w/sub deleted, the one after it misses /w/sub.
+	_ = d.WriteFile("/w/c.txt", []byte("c"))
+	got := collect(t, events, errc, 3)
+	want := []string{"created disk:/w/c.txt", "deleted disk:/w/sub", "deleted disk:/w/sub/b.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Watch() events = %v, want %v", got, want)
+	}
+}
+
+func TestDiffLive(t *testing.T) {
+	d := yadisktest.New(nil)
+	_ = d.WriteFile("/s/a.txt", []byte("a"))
+	_ = d.WriteFile("/s/b.txt", []byte("b"))
+	_ = d.WriteFile("/s/c.txt", []byte("c"))
+	ctx := context.Background()
+
+	s, err := yadisk.TakeSnapshot(ctx, d, "/s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := s.Entries["disk:/s/a.txt"]; e == nil || e.Sha256 == "" || e.Md5 == "" {
+		t.Fatalf("TakeSnapshot() entry = %+v", e)
+	}
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = yadisk.ReadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = d.WriteFile("/s/a.txt", []byte("changed"))
+	_ = d.WriteFile("/s/d.txt", []byte("d"))
+	if _, err := d.DeleteResource("/s/b.txt", nil, false, "", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.MoveResource("/s/c.txt", "/s/e.txt", nil, false, false); err != nil {
+		t.Fatal(err)
+	}
+	events, live, err := yadisk.DiffLive(ctx, d, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ev := range events {
+		got = append(got, string(ev.Type)+" "+string(ev.Path))
+	}
+	want := []string{"modified disk:/s/a.txt", "deleted disk:/s/b.txt", "created disk:/s/d.txt", "moved disk:/s/e.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("DiffLive() = %v, want %v", got, want)
+	}
+	if len(live.Entries) != 3 {
+		t.Errorf("DiffLive() snapshot entries = %d, want 3", len(live.Entries))
+	}
+}
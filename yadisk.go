@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"strings"
 	"sync"
 )
 
@@ -31,7 +30,7 @@ func NewYaDisk(ctx context.Context, client *http.Client, token *Token) (YaDisk,
 // Get user disk meta information.
 func (yad *yandexDisk) GetDisk(fields []string) (d *Disk, e error) {
 	values := url.Values{}
-	values.Add("fields", strings.Join(fields, ","))
+	addFields(values, fields)
 
 	req, e := yad.client.request(http.MethodGet, "/disk?"+values.Encode(), nil)
 	if e != nil {
@@ -0,0 +1,303 @@
+package yadisktest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette without network access.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the next RoundTripper and records them.
+	ModeRecord
+)
+
+const (
+	cassetteVersion = 1
+	redacted        = "REDACTED"
+)
+
+// DefaultRedactParams are the query parameters whose values are redacted from recorded URLs and hrefs.
+var DefaultRedactParams = []string{"access_token", "hash", "oauth_token", "sign", "tknv", "token", "uid"}
+
+// DefaultRedactHosts are the suffixes of the hosts whose URL paths hold signatures, those of the upload
+// and download hrefs.
+var DefaultRedactHosts = []string{".disk.yandex.net", ".disk.yandex.ru"}
+
+var urlRegexp = regexp.MustCompile(`https?://([^/?#\s"\\]+)(/[^?#\s"\\]*)`)
+
+// Cassette is the file format of a Recorder.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the redacted request of an Interaction.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// RecordedResponse is the redacted response of an Interaction.
+type RecordedResponse struct {
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	// Body of a UTF-8 response.
+	Body string `json:"body,omitempty"`
+	// Body of a binary response, base64 encoded.
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records request/response pairs to a cassette file and replays them.
+//
+// Requests are matched on method, path and query. Identical requests are replayed in the order they were recorded.
+// The Authorization header, the values of RedactParams and the path segments of the URLs on RedactHosts are
+// redacted in URLs, response headers and hrefs, consistently, so replayed hrefs still match the recorded requests
+// made to them. The values of the cookies set by responses are redacted too.
+type Recorder struct {
+	// Query parameters to redact, DefaultRedactParams if nil.
+	RedactParams []string
+	// Suffixes of the hosts whose URL paths are redacted but for the first segment, DefaultRedactHosts if nil.
+	RedactHosts []string
+
+	mode     Mode
+	path     string
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+
+	// Pattern of the RedactParams, compiled on the first use.
+	paramsOnce   sync.Once
+	paramsRegexp *regexp.Regexp
+}
+
+// NewRecorder returns a Recorder for the cassette file at path.
+// In ModeReplay the file is loaded, in ModeRecord requests go to next, http.DefaultTransport if nil.
+func NewRecorder(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{mode: mode, path: path, next: next, cassette: &Cassette{Version: cassetteVersion}}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	if e := json.Unmarshal(data, r.cassette); e != nil {
+		return nil, e
+	}
+	if r.cassette.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s: unsupported version %d", path, r.cassette.Version)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Client returns a client using the recorder, to be passed to yadisk.NewYaDisk.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, e := json.MarshalIndent(r.cassette, "", "  ")
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(r.path, data, 0644)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, e := r.next.RoundTrip(req)
+	if e != nil {
+		return nil, e
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if e != nil {
+		return nil, e
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := cloneHeader(req.Header)
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+	rec := RecordedResponse{Status: resp.Status, StatusCode: resp.StatusCode, Header: cloneHeader(resp.Header)}
+	if utf8.Valid(body) {
+		rec.Body = r.redact(string(body))
+	} else {
+		rec.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	for k, values := range rec.Header {
+		for i, v := range values {
+			if k == "Set-Cookie" {
+				values[i] = redactCookie(v)
+			} else {
+				values[i] = r.redact(v)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: r.redact(req.URL.String()), Header: header},
+		Response: rec,
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(in.Request, req) {
+			continue
+		}
+		r.used[i] = true
+		body := []byte(in.Response.Body)
+		if in.Response.BodyBase64 != "" {
+			var e error
+			if body, e = base64.StdEncoding.DecodeString(in.Response.BodyBase64); e != nil {
+				return nil, e
+			}
+		}
+		return &http.Response{
+			Status:        in.Response.Status,
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        cloneHeader(in.Response.Header),
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s: no recorded interaction for %s %s", r.path, req.Method, req.URL)
+}
+
+func (r *Recorder) matches(rec RecordedRequest, req *http.Request) bool {
+	if rec.Method != req.Method {
+		return false
+	}
+	a, e := url.Parse(rec.URL)
+	if e != nil {
+		return false
+	}
+	b, e := url.Parse(r.redact(req.URL.String()))
+	if e != nil {
+		return false
+	}
+	return a.Path == b.Path && a.Query().Encode() == b.Query().Encode()
+}
+
+// redact replaces the values of RedactParams in every URL query found in s and the signed path segments
+// of the URLs on RedactHosts.
+func (r *Recorder) redact(s string) string {
+	r.paramsOnce.Do(func() {
+		params := r.RedactParams
+		if params == nil {
+			params = DefaultRedactParams
+		}
+		if len(params) == 0 {
+			return
+		}
+		quoted := make([]string, len(params))
+		for i, p := range params {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		r.paramsRegexp = regexp.MustCompile(`([?&]|\\u0026)(` + strings.Join(quoted, "|") + `)=[^&"'<>\\\s]*`)
+	})
+	if r.paramsRegexp != nil {
+		s = r.paramsRegexp.ReplaceAllString(s, "${1}${2}="+redacted)
+	}
+	return urlRegexp.ReplaceAllStringFunc(s, r.redactPath)
+}
+
+// redactCookie replaces the value of a Set-Cookie header, keeping the name and the attributes.
+func redactCookie(v string) string {
+	i := strings.Index(v, "=")
+	if i < 0 {
+		return v
+	}
+	end := strings.Index(v, ";")
+	if end < i {
+		end = len(v)
+	}
+	return v[:i+1] + redacted + v[end:]
+}
+
+// redactPath replaces the path segments of u but for the first by a digest, if u is on one of RedactHosts.
+// Equal segments get equal digests, so distinct hrefs stay distinct.
+func (r *Recorder) redactPath(u string) string {
+	hosts := r.RedactHosts
+	if hosts == nil {
+		hosts = DefaultRedactHosts
+	}
+	m := urlRegexp.FindStringSubmatch(u)
+	host := strings.Split(m[1], ":")[0]
+	signed := false
+	for _, h := range hosts {
+		if strings.HasSuffix(host, h) {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return u
+	}
+	segments := strings.Split(m[2], "/")
+	for i := 2; i < len(segments); i++ {
+		if segments[i] == "" || strings.HasPrefix(segments[i], redacted+"-") {
+			continue
+		}
+		sum := sha256.Sum256([]byte(segments[i]))
+		segments[i] = redacted + "-" + hex.EncodeToString(sum[:6])
+	}
+	return strings.TrimSuffix(u, m[2]) + strings.Join(segments, "/")
+}
+
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
@@ -0,0 +1,121 @@
+package yadisktest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "session.json")
+
+	run := func(rec *Recorder) (*yadisk.Resource, error) {
+		yaDisk, _ := yadisk.NewYaDisk(context.Background(), rec.Client(), &yadisk.Token{AccessToken: "secret"})
+		link, err := yaDisk.GetResourceUploadLink("/a.txt", nil, false)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := yaDisk.PerformUpload(link, bytes.NewBufferString("data")); err != nil {
+			return nil, err
+		}
+		return yaDisk.GetResource("/a.txt", nil, 0, 0, false, "", "")
+	}
+
+	s := NewServer(nil)
+	rec, _ := NewRecorder(file, ModeRecord, s.Client().Transport)
+	recorded, err := run(rec)
+	s.Close()
+	if err != nil {
+		t.Fatalf("record error = %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Recorder.Save() error = %v", err)
+	}
+	data, _ := ioutil.ReadFile(file)
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("cassette contains the token: %s", data)
+	}
+
+	rec, err = NewRecorder(file, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	replayed, err := run(rec)
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	if replayed.Md5 != recorded.Md5 || replayed.ResourceID != recorded.ResourceID {
+		t.Errorf("replayed = %+v, want %+v", replayed, recorded)
+	}
+	if _, err := run(rec); err == nil {
+		t.Errorf("replay of an exhausted cassette error = nil")
+	}
+}
+
+func TestRecorder_redact(t *testing.T) {
+	r := &Recorder{}
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"url", "https://downloader.disk.yandex.ru/disk/x?uid=1&filename=a.txt&hash=abc", "https://downloader.disk.yandex.ru/disk/REDACTED-2d711642b726?uid=REDACTED&filename=a.txt&hash=REDACTED"},
+		{"json", `{"href":"https://d/x?filename=a&tknv=v2"}`, `{"href":"https://d/x?filename=a&tknv=REDACTED"}`},
+		{"untouched", "https://d/x?filename=a&fsize=1", "https://d/x?filename=a&fsize=1"},
+		{"signed_path", `{"href":"https://downloader.disk.yandex.ru/disk/5f2c/0a1b?uid=1"}`,
+			`{"href":"https://downloader.disk.yandex.ru/disk/REDACTED-c5520470c5c5/REDACTED-f47f40d94398?uid=REDACTED"}`},
+		{"redacted_path", "https://uploader1j.disk.yandex.net:443/upload-target/REDACTED-c5520470c5c5",
+			"https://uploader1j.disk.yandex.net:443/upload-target/REDACTED-c5520470c5c5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.redact(tt.s); got != tt.want {
+				t.Errorf("Recorder.redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecorder_redactHeaders(t *testing.T) {
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		h := make(http.Header)
+		h.Set("Location", "https://d/x?filename=a&sign=s")
+		h.Add("Set-Cookie", "yandexuid=123; Path=/; Secure")
+		h.Add("Set-Cookie", "session=abc")
+		h.Set("Link", "<https://d/x?token=t>; rel=next")
+		return &http.Response{Status: "302 Found", StatusCode: 302, Header: h, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	})
+	rec, _ := NewRecorder("", ModeRecord, next)
+	req, _ := http.NewRequest(http.MethodGet, "https://d/x", nil)
+	if _, err := rec.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	got := rec.cassette.Interactions[0].Response.Header
+	want := http.Header{
+		"Location":   {"https://d/x?filename=a&sign=REDACTED"},
+		"Set-Cookie": {"yandexuid=REDACTED; Path=/; Secure", "session=REDACTED"},
+		"Link":       {"<https://d/x?token=REDACTED>; rel=next"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded header = %v, want %v", got, want)
+	}
+}
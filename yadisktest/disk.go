@@ -0,0 +1,413 @@
+// Package yadisktest provides an in-memory implementation of yadisk.YaDisk for unit tests.
+package yadisktest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+const (
+	// DefaultTotalSpace is the disk size used when Options.TotalSpace is zero.
+	DefaultTotalSpace int64 = 10 << 30
+	// DefaultTransferURL is the base of upload and download hrefs handed out by a fake Disk.
+	DefaultTransferURL = "https://transfer.yadisktest.invalid"
+
+	defaultAppName = "yadisktest"
+	defaultLimit   = 20
+
+	diskRoot  = "disk:/"
+	trashRoot = "trash:/"
+
+	typeDir  = "dir"
+	typeFile = "file"
+)
+
+// Options configure a fake Disk. The zero value is usable.
+type Options struct {
+	// Delay after which asynchronous operations report success.
+	OperationDelay time.Duration
+	// Disk size in bytes, DefaultTotalSpace if zero.
+	TotalSpace int64
+	// Maximum size of a single file, yadisk.MaxFileUploadSize if zero.
+	MaxFileSize int64
+	// Owner of the disk.
+	User yadisk.User
+	// Name of the folder inside disk:/Applications that app:/ paths resolve to.
+	AppName string
+	// Clock used for timestamps and operation delays, time.Now if nil.
+	Now func() time.Time
+	// Client used by UploadExternalResource to fetch the external URL, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Disk is an in-memory Yandex.Disk.
+//
+// It keeps a tree of files and folders with revisions, the trash, published resources,
+// asynchronous operations and upload links, and answers with the same Error IDs as the real service.
+// The fields parameter of every method is ignored, full resources are always returned.
+type Disk struct {
+	mu          sync.Mutex
+	opts        Options
+	nodes       map[string]*node
+	ops         map[string]*operation
+	uploads     map[string]*upload
+	public      map[string]*node
+	revision    int64
+	seq         int64
+	apiURL      string
+	transferURL string
+}
+
+var _ yadisk.YaDisk = (*Disk)(nil)
+
+type node struct {
+	path       string
+	typ        string
+	resourceID string
+	data       []byte
+	md5        string
+	sha256     string
+	created    time.Time
+	modified   time.Time
+	revision   int64
+	props      map[string]interface{}
+	publicKey  string
+	publicURL  string
+	originPath string
+	deleted    time.Time
+}
+
+// New returns an empty fake Disk.
+func New(opts *Options) *Disk {
+	d := &Disk{
+		nodes:       make(map[string]*node),
+		ops:         make(map[string]*operation),
+		uploads:     make(map[string]*upload),
+		public:      make(map[string]*node),
+		apiURL:      yadisk.BaseURL,
+		transferURL: DefaultTransferURL,
+	}
+	if opts != nil {
+		d.opts = *opts
+	}
+	if d.opts.TotalSpace == 0 {
+		d.opts.TotalSpace = DefaultTotalSpace
+	}
+	if d.opts.MaxFileSize == 0 {
+		d.opts.MaxFileSize = yadisk.MaxFileUploadSize
+	}
+	if d.opts.AppName == "" {
+		d.opts.AppName = defaultAppName
+	}
+	if d.opts.User.UID == "" {
+		d.opts.User.UID = "1000000"
+	}
+	if d.opts.User.Login == "" {
+		d.opts.User.Login = "yadisktest"
+	}
+	if d.opts.Now == nil {
+		d.opts.Now = time.Now
+	}
+	if d.opts.HTTPClient == nil {
+		d.opts.HTTPClient = http.DefaultClient
+	}
+	d.revision = 1
+	d.nodes[diskRoot] = d.newNode(diskRoot, typeDir)
+	d.nodes[trashRoot] = d.newNode(trashRoot, typeDir)
+	return d
+}
+
+// Get user disk meta information.
+func (d *Disk) GetDisk(fields []string) (*yadisk.Disk, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var used, trash int64
+	for p, n := range d.nodes {
+		used += int64(len(n.data))
+		if within(p, trashRoot) {
+			trash += int64(len(n.data))
+		}
+	}
+	disk := new(yadisk.Disk)
+	disk.MaxFileSize = d.opts.MaxFileSize
+	disk.TotalSpace = d.opts.TotalSpace
+	disk.UsedSpace = used
+	disk.TrashSize = trash
+	disk.Revision = d.revision
+	disk.User = d.opts.User
+	disk.SystemFolders.Downloads = "disk:/Downloads/"
+	disk.SystemFolders.Applications = "disk:/Applications"
+	return disk, nil
+}
+
+func (d *Disk) now() time.Time {
+	return d.opts.Now().UTC().Truncate(time.Second)
+}
+
+func (d *Disk) bump() int64 {
+	d.revision++
+	return d.revision
+}
+
+func (d *Disk) hash(kind string) string {
+	d.seq++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", kind, d.seq)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (d *Disk) newNode(p string, typ string) *node {
+	now := d.now()
+	return &node{
+		path:       p,
+		typ:        typ,
+		resourceID: d.opts.User.UID + ":" + d.hash("resource"),
+		created:    now,
+		modified:   now,
+		revision:   d.bump(),
+	}
+}
+
+func (d *Disk) setData(n *node, data []byte) {
+	md5Sum, sha256Sum := checksums(data)
+	n.data = data
+	n.md5 = md5Sum
+	n.sha256 = sha256Sum
+	n.modified = d.now()
+	n.revision = d.bump()
+}
+
+func (d *Disk) usedSpace() int64 {
+	var used int64
+	for _, n := range d.nodes {
+		used += int64(len(n.data))
+	}
+	return used
+}
+
+// resolve converts a path as accepted by the API into a node key such as "disk:/a/b".
+// Only disk:, app: and trash: are roots, a path without one is on the Disk even if it contains a colon.
+func (d *Disk) resolve(p string) (string, error) {
+	if p == "" {
+		return "", newError(yadisk.ErrorIDFieldValidation)
+	}
+	ns := "disk:"
+	for _, root := range []string{"disk:", "app:", "trash:"} {
+		if strings.HasPrefix(p, root) {
+			ns, p = root, strings.TrimPrefix(p, root)
+			break
+		}
+	}
+	if ns == "app:" {
+		ns, p = "disk:", d.appFolder()+"/"+p
+	}
+	return ns + path.Clean("/"+p), nil
+}
+
+// appFolder returns the folder app:/ paths resolve to, without the disk: root.
+func (d *Disk) appFolder() string {
+	return path.Join("/Applications", d.opts.AppName)
+}
+
+func (d *Disk) lookup(p string) (string, *node, error) {
+	key, e := d.resolve(p)
+	if e != nil {
+		return "", nil, e
+	}
+	n, ok := d.nodes[key]
+	if !ok {
+		return key, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	return key, n, nil
+}
+
+func (d *Disk) children(p string) []*node {
+	var items []*node
+	for k, n := range d.nodes {
+		if k != p && parentOf(k) == p {
+			items = append(items, n)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].path < items[j].path })
+	return items
+}
+
+// subtree returns the keys of p and all of its descendants, parents first.
+func (d *Disk) subtree(p string) []string {
+	var keys []string
+	for k := range d.nodes {
+		if within(k, p) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d *Disk) removeTree(p string) {
+	for _, k := range d.subtree(p) {
+		if n := d.nodes[k]; n.publicKey != "" {
+			delete(d.public, n.publicKey)
+			delete(d.public, n.publicURL)
+		}
+		delete(d.nodes, k)
+	}
+}
+
+func (d *Disk) doc(n *node) map[string]interface{} {
+	m := map[string]interface{}{
+		"name":        baseOf(n.path),
+		"path":        n.path,
+		"type":        n.typ,
+		"resource_id": n.resourceID,
+		"created":     n.created.Format(time.RFC3339),
+		"modified":    n.modified.Format(time.RFC3339),
+		"revision":    n.revision,
+	}
+	if n.typ == typeFile {
+		m["size"] = len(n.data)
+		m["md5"] = n.md5
+		m["sha256"] = n.sha256
+		m["mime_type"] = mimeType(n.path)
+		m["media_type"] = mediaType(n.path)
+		if within(n.path, diskRoot) {
+			m["file"] = d.downloadHref(n.path, "")
+		}
+	}
+	if n.publicKey != "" {
+		m["public_key"] = n.publicKey
+		m["public_url"] = n.publicURL
+	}
+	if len(n.props) > 0 {
+		m["custom_properties"] = n.props
+	}
+	if n.originPath != "" {
+		m["origin_path"] = n.originPath
+		m["deleted"] = n.deleted.Format(time.RFC3339)
+	}
+	return m
+}
+
+func (d *Disk) embedded(p string, items []*node, limit int, offset int, sortBy string) (map[string]interface{}, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if e := sortNodes(items, sortBy); e != nil {
+		return nil, e
+	}
+	total := len(items)
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	docs := make([]map[string]interface{}, len(items))
+	for i, n := range items {
+		docs[i] = d.doc(n)
+	}
+	return map[string]interface{}{
+		"path":   p,
+		"limit":  limit,
+		"offset": offset,
+		"total":  total,
+		"sort":   sortBy,
+		"items":  docs,
+	}, nil
+}
+
+func (d *Disk) apiLink(pathURL string) *yadisk.Link {
+	return &yadisk.Link{Href: d.apiURL + "/v1/disk" + pathURL, Method: http.MethodGet}
+}
+
+func (d *Disk) resourceLink(p string) *yadisk.Link {
+	values := url.Values{}
+	values.Add("path", p)
+	if within(p, trashRoot) {
+		return d.apiLink("/trash/resources?" + values.Encode())
+	}
+	return d.apiLink("/resources?" + values.Encode())
+}
+
+func (d *Disk) newPublicKey() (string, string) {
+	sum := sha256.Sum256([]byte(d.hash("public")))
+	key := base64.StdEncoding.EncodeToString(sum[:])
+	return key, "https://yadi.sk/d/" + base64.RawURLEncoding.EncodeToString(sum[:])[:14]
+}
+
+func sortNodes(items []*node, sortBy string) error {
+	desc := strings.HasPrefix(sortBy, "-")
+	var less func(a, b *node) bool
+	switch strings.TrimPrefix(sortBy, "-") {
+	case "", "name":
+		less = func(a, b *node) bool { return baseOf(a.path) < baseOf(b.path) }
+	case "path":
+		less = func(a, b *node) bool { return a.path < b.path }
+	case "created":
+		less = func(a, b *node) bool { return a.created.Before(b.created) }
+	case "modified":
+		less = func(a, b *node) bool { return a.modified.Before(b.modified) }
+	case "size":
+		less = func(a, b *node) bool { return len(a.data) < len(b.data) }
+	case "deleted":
+		less = func(a, b *node) bool { return a.deleted.Before(b.deleted) }
+	default:
+		return newError(yadisk.ErrorIDFieldValidation)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+	return nil
+}
+
+// convert fills the SDK type dst from the JSON document src, the same way the client decodes responses.
+func convert(src interface{}, dst interface{}) error {
+	data, e := json.Marshal(src)
+	if e != nil {
+		return e
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func parentOf(p string) string {
+	i := strings.Index(p, ":") + 1
+	return p[:i] + path.Dir(p[i:])
+}
+
+func baseOf(p string) string {
+	i := strings.Index(p, ":") + 1
+	if p[i:] == "/" {
+		return p[i:]
+	}
+	return path.Base(p[i:])
+}
+
+func within(p string, root string) bool {
+	return p == root || strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/")
+}
+
+func rebase(p string, from string, to string) string {
+	if p == from {
+		return to
+	}
+	return strings.TrimSuffix(to, "/") + strings.TrimPrefix(p, strings.TrimSuffix(from, "/"))
+}
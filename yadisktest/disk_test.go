@@ -0,0 +1,213 @@
+package yadisktest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+func newTestDisk(t *testing.T, files map[string]string) *Disk {
+	d := New(nil)
+	for p, data := range files {
+		if err := d.WriteFile(p, []byte(data)); err != nil {
+			t.Fatalf("Disk.WriteFile(%q) error = %v", p, err)
+		}
+	}
+	return d
+}
+
+func TestDisk_errors(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/a.txt": "a", "/b/c.txt": "c"})
+	if _, err := d.CreateResource("/dir", nil); err != nil {
+		t.Fatalf("Disk.CreateResource() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		call    func() error
+		errorID string
+	}{
+		{"not_found", func() error { _, e := d.GetResource("/missing", nil, 0, 0, false, "", ""); return e }, yadisk.ErrorIDNotFound},
+		{"mkdir_existing", func() error { _, e := d.CreateResource("/dir", nil); return e }, yadisk.ErrorIDPathPointsToExistentDir},
+		{"mkdir_no_parent", func() error { _, e := d.CreateResource("/x/y", nil); return e }, yadisk.ErrorIDPathDoesntExist},
+		{"copy_into_itself", func() error { _, e := d.CopyResource("/a.txt", "/a.txt", nil, false, false); return e }, yadisk.ErrorIDFieldValidation},
+		{"move_over_parent", func() error { _, e := d.MoveResource("/b/c.txt", "/b", nil, false, true); return e }, yadisk.ErrorIDFieldValidation},
+		{"upload_existing", func() error { _, e := d.GetResourceUploadLink("/a.txt", nil, false); return e }, yadisk.ErrorIDResourceAlreadyExists},
+		{"delete_md5", func() error { _, e := d.DeleteResource("/a.txt", nil, false, "bad", false); return e }, yadisk.ErrorIDMD5Differ},
+		{"operation", func() error { _, e := d.GetOperationStatus("missing", nil); return e }, yadisk.ErrorIDOperationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !yadisk.IsErrorID(err, tt.errorID) {
+				t.Errorf("error = %v, want %v", err, tt.errorID)
+			}
+		})
+	}
+}
+
+func TestDisk_GetResource(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/b.txt": "bb", "/a.txt": "a", "/c.txt": "ccc"})
+	r, err := d.GetResource("disk:/", nil, 2, 1, false, "", "-size")
+	if err != nil {
+		t.Fatalf("Disk.GetResource() error = %v", err)
+	}
+	if r.Embedded.Total != 3 || len(r.Embedded.Items) != 2 {
+		t.Fatalf("Disk.GetResource() total = %d, items = %d", r.Embedded.Total, len(r.Embedded.Items))
+	}
+	if r.Embedded.Items[0].Name != "b.txt" || r.Embedded.Items[1].Path != "disk:/a.txt" {
+		t.Errorf("Disk.GetResource() items = %v, %v", r.Embedded.Items[0].Path, r.Embedded.Items[1].Path)
+	}
+	if r.Embedded.Items[0].Md5 != "21ad0bd836b90d08f4cf640b4c298e7c" {
+		t.Errorf("Disk.GetResource() md5 = %v", r.Embedded.Items[0].Md5)
+	}
+}
+
+func TestDisk_PerformUpload(t *testing.T) {
+	d := newTestDisk(t, nil)
+	link, err := d.GetResourceUploadLink("/a.txt", nil, false)
+	if err != nil {
+		t.Fatalf("Disk.GetResourceUploadLink() error = %v", err)
+	}
+	if _, err := d.PerformUpload(link, bytes.NewBufferString("data")); err != nil {
+		t.Fatalf("Disk.PerformUpload() error = %v", err)
+	}
+	if _, err := d.PerformUpload(link, bytes.NewBufferString("data")); !yadisk.IsErrorID(err, "404") {
+		t.Errorf("Disk.PerformUpload() reused link error = %v, want 404", err)
+	}
+	if _, err := d.PerformPartialUpload(link, nil, 1); err == nil {
+		t.Error("Disk.PerformPartialUpload() with nil data error = nil")
+	}
+}
+
+func TestDisk_paths(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/a:b.txt": "a"})
+	if r, err := d.GetResource("disk:/a:b.txt", nil, 0, 0, false, "", ""); err != nil || r.Path != "disk:/a:b.txt" {
+		t.Errorf("Disk.GetResource() = %v, %v", r, err)
+	}
+	before, _ := d.GetDisk(nil)
+	if _, err := d.GetResource("app:/", nil, 0, 0, false, "", ""); !yadisk.IsErrorID(err, yadisk.ErrorIDNotFound) {
+		t.Errorf("Disk.GetResource() of the application folder before a write error = %v", err)
+	}
+	if after, _ := d.GetDisk(nil); after.Revision != before.Revision {
+		t.Errorf("Disk.GetResource() changed the revision from %d to %d", before.Revision, after.Revision)
+	}
+	if _, err := d.CreateResource("app:/dir", nil); err != nil {
+		t.Fatalf("Disk.CreateResource() error = %v", err)
+	}
+	if _, err := d.GetResource("app:/dir", nil, 0, 0, false, "", ""); err != nil {
+		t.Errorf("Disk.GetResource() error = %v", err)
+	}
+}
+
+func TestDisk_MoveResource(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/a.txt": "a"})
+	before, _ := d.GetResource("/a.txt", nil, 0, 0, false, "", "")
+	if _, err := d.MoveResource("/a.txt", "/b.txt", nil, false, false); err != nil {
+		t.Fatalf("Disk.MoveResource() error = %v", err)
+	}
+	after, err := d.GetResource("/b.txt", nil, 0, 0, false, "", "")
+	if err != nil {
+		t.Fatalf("Disk.GetResource() error = %v", err)
+	}
+	if after.ResourceID != before.ResourceID || after.Revision <= before.Revision {
+		t.Errorf("Disk.MoveResource() resource = %v/%v, want %v/>%v", after.ResourceID, after.Revision, before.ResourceID, before.Revision)
+	}
+}
+
+func TestDisk_trash(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/dir/a.txt": "a"})
+	link, err := d.DeleteResource("/dir", nil, false, "", false)
+	if err != nil || link == nil {
+		t.Fatalf("Disk.DeleteResource() = %v, %v, want operation link", link, err)
+	}
+	trash, err := d.GetTrashResource("", nil, 0, 0, false, "", "")
+	if err != nil {
+		t.Fatalf("Disk.GetTrashResource() error = %v", err)
+	}
+	if len(trash.Embedded.Items) != 1 || trash.Embedded.Items[0].OriginPath != "disk:/dir" || trash.Embedded.Items[0].Deleted.IsZero() {
+		t.Fatalf("Disk.GetTrashResource() items = %+v", trash.Embedded.Items)
+	}
+	if _, err := d.RestoreFromTrash(trash.Embedded.Items[0].Path, nil, false, "", false); err != nil {
+		t.Fatalf("Disk.RestoreFromTrash() error = %v", err)
+	}
+	if _, err := d.GetResource("/dir/a.txt", nil, 0, 0, false, "", ""); err != nil {
+		t.Errorf("Disk.GetResource() after restore error = %v", err)
+	}
+}
+
+func TestDisk_public(t *testing.T) {
+	d := newTestDisk(t, map[string]string{"/pub/sub/a.txt": "public data"})
+	if _, err := d.PublishResource("/pub", nil); err != nil {
+		t.Fatalf("Disk.PublishResource() error = %v", err)
+	}
+	r, _ := d.GetResource("/pub", nil, 0, 0, false, "", "")
+	pr, err := d.GetPublicResource(r.PublicURL, nil, 0, 0, "/sub", false, "", "")
+	if err != nil {
+		t.Fatalf("Disk.GetPublicResource() error = %v", err)
+	}
+	if pr.Path != "/sub" || len(pr.Embedded.Items) != 1 || pr.Embedded.Items[0].Path != "/sub/a.txt" {
+		t.Fatalf("Disk.GetPublicResource() = %+v", pr)
+	}
+	link, err := d.GetPublicResourceDownloadLink(r.PublicKey, nil, "/sub/a.txt")
+	if err != nil {
+		t.Fatalf("Disk.GetPublicResourceDownloadLink() error = %v", err)
+	}
+	resp, err := d.HTTPClient().Get(link.Href)
+	if err != nil {
+		t.Fatalf("download error = %v", err)
+	}
+	data, _ := ioutil.ReadAll(resp.Body)
+	if string(data) != "public data" {
+		t.Errorf("download = %q", data)
+	}
+}
+
+func TestDisk_HTTPClient_upload(t *testing.T) {
+	d := newTestDisk(t, nil)
+	link, err := d.GetResourceUploadLink("/big.bin", nil, false)
+	if err != nil {
+		t.Fatalf("Disk.GetResourceUploadLink() error = %v", err)
+	}
+	// The repeated chunk must not complete the upload.
+	for _, part := range []struct{ rng, data string }{{"bytes 3-5/6", "def"}, {"bytes 3-5/6", "def"}, {"bytes 0-2/6", "abc"}} {
+		req, _ := http.NewRequest(link.Method, link.Href, bytes.NewBufferString(part.data))
+		req.Header.Set("Content-Range", part.rng)
+		if _, err := d.HTTPClient().Do(req); err != nil {
+			t.Fatalf("upload error = %v", err)
+		}
+	}
+	r, err := d.GetResource("/big.bin", nil, 0, 0, false, "", "")
+	if err != nil || r.Size != 6 || r.Md5 != "e80b5017098950fc58aad83c8c14978e" {
+		t.Fatalf("Disk.GetResource() = %v, %v", r, err)
+	}
+	status, _ := d.GetOperationStatus(link.OperationID, nil)
+	if status.Status != statusSuccess {
+		t.Errorf("Disk.GetOperationStatus() = %v", status.Status)
+	}
+}
+
+func TestDisk_operationDelay(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := New(&Options{OperationDelay: time.Minute, Now: func() time.Time { return now }})
+	if _, err := d.CreateResource("/dir", nil); err != nil {
+		t.Fatalf("Disk.CreateResource() error = %v", err)
+	}
+	link, err := d.CopyResource("/dir", "/copy", nil, true, false)
+	if err != nil {
+		t.Fatalf("Disk.CopyResource() error = %v", err)
+	}
+	id := link.Href[len(link.Href)-64:]
+	for _, tt := range []struct {
+		after time.Duration
+		want  string
+	}{{0, statusInProgress}, {time.Minute, statusSuccess}} {
+		now = now.Add(tt.after)
+		s, err := d.GetOperationStatus(id, nil)
+		if err != nil || s.Status != tt.want {
+			t.Errorf("Disk.GetOperationStatus() = %v, %v, want %v", s, err, tt.want)
+		}
+	}
+}
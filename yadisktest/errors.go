@@ -0,0 +1,51 @@
+package yadisktest
+
+import (
+	"net/http"
+	"strconv"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+type apiError struct {
+	status      int
+	message     string
+	description string
+}
+
+var apiErrors = map[string]apiError{
+	yadisk.ErrorIDFieldValidation:         {http.StatusBadRequest, "Ошибка проверки поля.", "Error validating field."},
+	yadisk.ErrorIDUnauthorized:            {http.StatusUnauthorized, "Не авторизован.", "Unauthorized"},
+	yadisk.ErrorIDNotFound:                {http.StatusNotFound, "Не удалось найти запрошенный ресурс.", "Resource not found."},
+	yadisk.ErrorIDOperationNotFound:       {http.StatusNotFound, "Не удалось найти запрошенную операцию.", "Operation not found."},
+	yadisk.ErrorIDPathDoesntExist:         {http.StatusConflict, "Указанного пути не существует.", "Specified path doesn't exists."},
+	yadisk.ErrorIDPathPointsToExistentDir: {http.StatusConflict, "По указанному пути уже существует папка с таким именем.", "Specified path points to existent directory."},
+	yadisk.ErrorIDResourceAlreadyExists:   {http.StatusConflict, "Ресурс уже существует.", "Resource already exists."},
+	yadisk.ErrorIDMD5Differ:               {http.StatusConflict, "MD5 не совпадает.", "MD5 differs."},
+	yadisk.ErrorIDFileTooLarge:            {http.StatusRequestEntityTooLarge, "Размер файла больше допустимого.", "File too large."},
+	yadisk.ErrorIDTooManyRequests:         {http.StatusTooManyRequests, "Слишком много запросов.", "Too Many Requests."},
+	yadisk.ErrorIDStorageQuotaExhausted:   {http.StatusInsufficientStorage, "Недостаточно свободного места.", "Insufficient storage."},
+}
+
+func newError(errorID string) *yadisk.Error {
+	a := apiErrors[errorID]
+	return &yadisk.Error{ErrorID: errorID, Message: a.message, Description: a.description}
+}
+
+// StatusCode returns the HTTP status the real service answers with for an error returned by Disk.
+// Errors that are not an *yadisk.Error map to 500.
+func StatusCode(e error) int {
+	err, ok := e.(*yadisk.Error)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	if a, ok := apiErrors[err.ErrorID]; ok {
+		return a.status
+	}
+	return http.StatusInternalServerError
+}
+
+// uploadError reproduces the error PerformUpload of the real client returns for a failed PUT.
+func uploadError(status int) *yadisk.Error {
+	return &yadisk.Error{ErrorID: strconv.Itoa(status), Description: strconv.Itoa(status) + " " + http.StatusText(status)}
+}
@@ -0,0 +1,61 @@
+package yadisktest
+
+import (
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+const (
+	statusSuccess    = yadisk.OperationStatusSuccess
+	statusFailed     = yadisk.OperationStatusFailed
+	statusInProgress = yadisk.OperationStatusInProgress
+)
+
+type operation struct {
+	readyAt time.Time
+	pending bool
+	failed  bool
+}
+
+// Get the status of an asynchronous operation.
+//
+// Operations report "in-progress" until Options.OperationDelay has passed since they were started.
+func (d *Disk) GetOperationStatus(operationID string, fields []string) (*yadisk.OperationStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	op, ok := d.ops[operationID]
+	if !ok {
+		return nil, newError(yadisk.ErrorIDOperationNotFound)
+	}
+	s := &yadisk.OperationStatus{Status: statusSuccess}
+	switch {
+	case op.pending || d.opts.Now().Before(op.readyAt):
+		s.Status = statusInProgress
+	case op.failed:
+		s.Status = statusFailed
+	}
+	return s, nil
+}
+
+func (d *Disk) newOperation() (string, *operation) {
+	id := d.hash("operation")
+	op := &operation{readyAt: d.opts.Now().Add(d.opts.OperationDelay)}
+	d.ops[id] = op
+	return id, op
+}
+
+func (d *Disk) operationLink(id string) *yadisk.Link {
+	return d.apiLink("/operations/" + id)
+}
+
+// result returns the link answered by a copy-like call: a link to an asynchronous operation
+// when the call was forced to be asynchronous or touched a non-empty folder, the resource link otherwise.
+func (d *Disk) result(forceAsync bool, p string) *yadisk.Link {
+	if forceAsync || len(d.children(p)) > 0 {
+		id, _ := d.newOperation()
+		return d.operationLink(id)
+	}
+	return d.resourceLink(p)
+}
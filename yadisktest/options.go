@@ -0,0 +1,119 @@
+package yadisktest
+
+import (
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+var _ yadisk.YaDiskWithOptions = (*Disk)(nil)
+
+// Get meta information about a file or directory.
+func (d *Disk) GetResourceWithOptions(path string, opts *yadisk.ResourceOptions) (*yadisk.Resource, error) {
+	if opts == nil {
+		opts = new(yadisk.ResourceOptions)
+	}
+	return d.GetResource(path, opts.Fields, intValue(opts.Limit), intValue(opts.Offset), opts.PreviewCrop, string(opts.PreviewSize), string(opts.Sort))
+}
+
+// Get the contents of the Trash.
+func (d *Disk) GetTrashResourceWithOptions(path string, opts *yadisk.ResourceOptions) (*yadisk.TrashResource, error) {
+	if opts == nil {
+		opts = new(yadisk.ResourceOptions)
+	}
+	return d.GetTrashResource(path, opts.Fields, intValue(opts.Limit), intValue(opts.Offset), opts.PreviewCrop, string(opts.PreviewSize), string(opts.Sort))
+}
+
+// Get file list sorted by name.
+func (d *Disk) GetFlatFilesListWithOptions(opts *yadisk.FilesListOptions) (*yadisk.FilesResourceList, error) {
+	if opts == nil {
+		opts = new(yadisk.FilesListOptions)
+	}
+	return d.GetFlatFilesList(opts.Fields, intValue(opts.Limit), string(opts.MediaType), intValue(opts.Offset), opts.PreviewCrop, string(opts.PreviewSize), string(opts.Sort))
+}
+
+// Get a list of files ordered by download date.
+func (d *Disk) GetLastUploadedFilesListWithOptions(opts *yadisk.LastUploadedOptions) (*yadisk.LastUploadedResourceList, error) {
+	if opts == nil {
+		opts = new(yadisk.LastUploadedOptions)
+	}
+	return d.GetLastUploadedFilesList(opts.Fields, intValue(opts.Limit), string(opts.MediaType), opts.PreviewCrop, string(opts.PreviewSize))
+}
+
+// Get a list of published resources.
+func (d *Disk) ListPublicResourcesWithOptions(opts *yadisk.PublicResourcesListOptions) (*yadisk.PublicResourcesList, error) {
+	if opts == nil {
+		opts = new(yadisk.PublicResourcesListOptions)
+	}
+	return d.ListPublicResources(opts.Fields, intValue(opts.Limit), intValue(opts.Offset), opts.PreviewCrop, string(opts.PreviewSize), string(opts.Type))
+}
+
+// Get meta-information about a public file or directory.
+func (d *Disk) GetPublicResourceWithOptions(publicKey string, opts *yadisk.PublicResourceOptions) (*yadisk.PublicResource, error) {
+	if opts == nil {
+		opts = new(yadisk.PublicResourceOptions)
+	}
+	return d.GetPublicResource(publicKey, opts.Fields, intValue(opts.Limit), intValue(opts.Offset), opts.Path, opts.PreviewCrop, string(opts.PreviewSize), string(opts.Sort))
+}
+
+// Empty trash.
+func (d *Disk) ClearTrashWithOptions(opts *yadisk.ClearTrashOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.ClearTrashOptions)
+	}
+	return d.ClearTrash(opts.Fields, opts.ForceAsync, opts.Path)
+}
+
+// Recover Resource from Trash.
+func (d *Disk) RestoreFromTrashWithOptions(path string, opts *yadisk.RestoreFromTrashOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.RestoreFromTrashOptions)
+	}
+	return d.RestoreFromTrash(path, opts.Fields, opts.ForceAsync, opts.Name, opts.Overwrite)
+}
+
+// Delete file or folder.
+func (d *Disk) DeleteResourceWithOptions(path string, opts *yadisk.DeleteOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.DeleteOptions)
+	}
+	return d.DeleteResource(path, opts.Fields, opts.ForceAsync, opts.Md5, opts.Permanently)
+}
+
+// Create a copy of the file or folder.
+func (d *Disk) CopyResourceWithOptions(from string, path string, opts *yadisk.TransferOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.TransferOptions)
+	}
+	return d.CopyResource(from, path, opts.Fields, opts.ForceAsync, opts.Overwrite)
+}
+
+// Move a file or folder.
+func (d *Disk) MoveResourceWithOptions(from string, path string, opts *yadisk.TransferOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.TransferOptions)
+	}
+	return d.MoveResource(from, path, opts.Fields, opts.ForceAsync, opts.Overwrite)
+}
+
+// Upload file to Disk by URL.
+func (d *Disk) UploadExternalResourceWithOptions(path string, externalURL string, opts *yadisk.UploadExternalOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.UploadExternalOptions)
+	}
+	return d.UploadExternalResource(path, externalURL, opts.DisableRedirects, opts.Fields)
+}
+
+// Save the public resource to the Downloads folder.
+func (d *Disk) SaveToDiskPublicResourceWithOptions(publicKey string, opts *yadisk.SaveToDiskOptions) (*yadisk.Link, error) {
+	if opts == nil {
+		opts = new(yadisk.SaveToDiskOptions)
+	}
+	return d.SaveToDiskPublicResource(publicKey, opts.Fields, opts.ForceAsync, opts.Name, opts.Path, opts.SavePath)
+}
+
+// intValue returns the value of p, zero if it is nil.
+func intValue(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
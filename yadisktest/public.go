@@ -0,0 +1,197 @@
+package yadisktest
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+// Get a list of published resources.
+//
+// resourceType value: "","dir","file".
+func (d *Disk) ListPublicResources(fields []string, limit int, offset int, previewCrop bool, previewSize string, resourceType string) (*yadisk.PublicResourcesList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if resourceType != "" && resourceType != typeDir && resourceType != typeFile {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	var items []*node
+	for key, n := range d.public {
+		if key == n.publicKey && (resourceType == "" || resourceType == n.typ) {
+			items = append(items, n)
+		}
+	}
+	emb, e := d.embedded("", items, limit, offset, "path")
+	if e != nil {
+		return nil, e
+	}
+	emb["type"] = resourceType
+	l := new(yadisk.PublicResourcesList)
+	return l, convert(emb, l)
+}
+
+// Publish a resource.
+func (d *Disk) PublishResource(path string, fields []string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, n, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	if n.publicKey == "" {
+		n.publicKey, n.publicURL = d.newPublicKey()
+		d.public[n.publicKey] = n
+		d.public[n.publicURL] = n
+		n.revision = d.bump()
+	}
+	return d.resourceLink(key), nil
+}
+
+// Unpublish a resource.
+func (d *Disk) UnpublishResource(path string, fields []string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, n, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	if n.publicKey != "" {
+		delete(d.public, n.publicKey)
+		delete(d.public, n.publicURL)
+		n.publicKey, n.publicURL = "", ""
+		n.revision = d.bump()
+	}
+	return d.resourceLink(key), nil
+}
+
+// Get meta-information about a public file or directory.
+//
+// publicKey may be either the public key or the public URL of the resource.
+func (d *Disk) GetPublicResource(publicKey string, fields []string, limit int, offset int, path string, previewCrop bool, previewSize string, sort string) (*yadisk.PublicResource, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	root, n, e := d.lookupPublic(publicKey, path)
+	if e != nil {
+		return nil, e
+	}
+	doc := d.publicDoc(root, n)
+	if n.typ == typeDir {
+		emb, e := d.embedded(relPath(root, n), d.children(n.path), limit, offset, sort)
+		if e != nil {
+			return nil, e
+		}
+		items := emb["items"].([]map[string]interface{})
+		for i, item := range items {
+			items[i] = d.publicDoc(root, d.nodes[item["path"].(string)])
+		}
+		emb["public_key"] = root.publicKey
+		doc["_embedded"] = emb
+	}
+	doc["views_count"] = 0
+	doc["owner"] = map[string]interface{}{
+		"login":        d.opts.User.Login,
+		"display_name": d.opts.User.DisplayName,
+		"uid":          d.opts.User.UID,
+	}
+	r := new(yadisk.PublicResource)
+	return r, convert(doc, r)
+}
+
+// Get a link to download a public resource.
+func (d *Disk) GetPublicResourceDownloadLink(publicKey string, fields []string, path string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	root, n, e := d.lookupPublic(publicKey, path)
+	if e != nil {
+		return nil, e
+	}
+	return &yadisk.Link{Href: d.downloadHref(relPath(root, n), root.publicKey), Method: "GET"}, nil
+}
+
+// Save the public resource to the Downloads folder.
+//
+// A numeric suffix is added to the name when the target already exists.
+func (d *Disk) SaveToDiskPublicResource(publicKey string, fields []string, forceAsync bool, name string, path string, savePath string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, n, e := d.lookupPublic(publicKey, path)
+	if e != nil {
+		return nil, e
+	}
+	dir := "disk:/Downloads"
+	if savePath != "" {
+		dir, e = d.resolveTarget(savePath)
+		if e != nil {
+			return nil, e
+		}
+		if parent, ok := d.nodes[dir]; !ok || parent.typ != typeDir {
+			return nil, newError(yadisk.ErrorIDPathDoesntExist)
+		}
+	} else {
+		d.mkdirAll(dir)
+	}
+	if name == "" {
+		name = baseOf(n.path)
+	}
+	dst := strings.TrimSuffix(dir, "/") + "/" + name
+	for i := 1; d.nodes[dst] != nil; i++ {
+		dst = strings.TrimSuffix(dir, "/") + "/" + numbered(name, i)
+	}
+	if e := d.copyTree(n.path, dst); e != nil {
+		return nil, e
+	}
+	return d.result(forceAsync, dst), nil
+}
+
+func (d *Disk) lookupPublic(publicKey string, p string) (*node, *node, error) {
+	root, ok := d.public[publicKey]
+	if !ok {
+		return nil, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	if p == "" || p == "/" {
+		return root, root, nil
+	}
+	if root.typ != typeDir {
+		return nil, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	n, ok := d.nodes[strings.TrimSuffix(root.path, "/")+path.Clean("/"+p)]
+	if !ok {
+		return nil, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	return root, n, nil
+}
+
+func (d *Disk) publicDoc(root *node, n *node) map[string]interface{} {
+	doc := d.doc(n)
+	doc["path"] = relPath(root, n)
+	doc["public_key"] = root.publicKey
+	delete(doc, "custom_properties")
+	if n != root {
+		delete(doc, "public_url")
+	}
+	if n.typ == typeFile {
+		doc["file"] = d.downloadHref(relPath(root, n), root.publicKey)
+	}
+	return doc
+}
+
+func relPath(root *node, n *node) string {
+	if n == root {
+		return "/"
+	}
+	return strings.TrimPrefix(n.path, strings.TrimSuffix(root.path, "/"))
+}
+
+// numbered inserts " (i)" before the extension of name.
+func numbered(name string, i int) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + " (" + strconv.Itoa(i) + ")" + ext
+}
@@ -0,0 +1,326 @@
+package yadisktest
+
+import (
+	"encoding/json"
+	"strings"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+// Delete file or folder.
+//
+// By default, delete the resource in the trash.
+// To delete a resource without placing it in the trash, you must specify the parameter permanently = true.
+func (d *Disk) DeleteResource(path string, fields []string, forceAsync bool, md5 string, permanently bool) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, n, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	if key == diskRoot {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	if md5 != "" && n.md5 != md5 {
+		return nil, newError(yadisk.ErrorIDMD5Differ)
+	}
+	async := forceAsync || len(d.children(key)) > 0
+	if permanently {
+		d.removeTree(key)
+		d.bump()
+	} else {
+		d.trash(key)
+	}
+	if async {
+		id, _ := d.newOperation()
+		return d.operationLink(id), nil
+	}
+	return nil, nil
+}
+
+// Get meta information about a file or directory.
+func (d *Disk) GetResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*yadisk.Resource, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, n, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	doc := d.doc(n)
+	if n.typ == typeDir {
+		doc["_embedded"], e = d.embedded(key, d.children(key), limit, offset, sort)
+		if e != nil {
+			return nil, e
+		}
+	}
+	r := new(yadisk.Resource)
+	return r, convert(doc, r)
+}
+
+// Create directory.
+func (d *Disk) CreateResource(path string, fields []string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, e := d.resolveTarget(path)
+	if e != nil {
+		return nil, e
+	}
+	if n, ok := d.nodes[key]; ok {
+		if n.typ == typeDir {
+			return nil, newError(yadisk.ErrorIDPathPointsToExistentDir)
+		}
+		return nil, newError(yadisk.ErrorIDResourceAlreadyExists)
+	}
+	if e := d.checkParent(key); e != nil {
+		return nil, e
+	}
+	d.nodes[key] = d.newNode(key, typeDir)
+	return d.resourceLink(key), nil
+}
+
+// Update User Resource Data.
+//
+// Keys of body.CustomProperties set to null are removed from the resource.
+func (d *Disk) UpdateResource(path string, fields []string, body *yadisk.ResourcePatch) (*yadisk.Resource, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, n, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	if body == nil {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	data, e := json.Marshal(body.CustomProperties)
+	if e != nil {
+		return nil, e
+	}
+	var patch map[string]interface{}
+	if e := json.Unmarshal(data, &patch); e != nil {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	if n.props == nil {
+		n.props = make(map[string]interface{})
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(n.props, k)
+			continue
+		}
+		n.props[k] = v
+	}
+	n.revision = d.bump()
+
+	r := new(yadisk.Resource)
+	return r, convert(d.doc(n), r)
+}
+
+// Create a copy of the file or folder.
+func (d *Disk) CopyResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*yadisk.Link, error) {
+	return d.transfer(false, from, path, forceAsync, overwrite)
+}
+
+// Move a file or folder.
+//
+// Moved resources keep their ResourceID.
+func (d *Disk) MoveResource(from string, path string, fields []string, forceAsync bool, overwrite bool) (*yadisk.Link, error) {
+	return d.transfer(true, from, path, forceAsync, overwrite)
+}
+
+func (d *Disk) transfer(move bool, from string, path string, forceAsync bool, overwrite bool) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	src, _, e := d.lookupDisk(from)
+	if e != nil {
+		return nil, e
+	}
+	dst, e := d.resolveTarget(path)
+	if e != nil {
+		return nil, e
+	}
+	if src == diskRoot || within(dst, src) {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	if e := d.checkParent(dst); e != nil {
+		return nil, e
+	}
+	if _, ok := d.nodes[dst]; ok {
+		if !overwrite {
+			return nil, newError(yadisk.ErrorIDResourceAlreadyExists)
+		}
+		// Replacing a folder the source is in would delete the source.
+		if within(src, dst) {
+			return nil, newError(yadisk.ErrorIDFieldValidation)
+		}
+		d.removeTree(dst)
+	}
+	if move {
+		d.rename(src, dst)
+	} else if e := d.copyTree(src, dst); e != nil {
+		return nil, e
+	}
+	return d.result(forceAsync, dst), nil
+}
+
+// Get link to download file.
+func (d *Disk) GetResourceDownloadLink(path string, fields []string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, _, e := d.lookupDisk(path)
+	if e != nil {
+		return nil, e
+	}
+	return &yadisk.Link{Href: d.downloadHref(key, ""), Method: "GET"}, nil
+}
+
+// Get file list sorted by name.
+//
+// mediaType may hold several comma-separated media types.
+func (d *Disk) GetFlatFilesList(fields []string, limit int, mediaType string, offset int, previewCrop bool, previewSize string, sort string) (*yadisk.FilesResourceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	emb, e := d.embedded(diskRoot, d.files(mediaType), limit, offset, sort)
+	if e != nil {
+		return nil, e
+	}
+	l := new(yadisk.FilesResourceList)
+	return l, convert(emb, l)
+}
+
+// Get a list of files ordered by download date.
+func (d *Disk) GetLastUploadedFilesList(fields []string, limit int, mediaType string, previewCrop bool, previewSize string) (*yadisk.LastUploadedResourceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	emb, e := d.embedded(diskRoot, d.files(mediaType), limit, 0, "-created")
+	if e != nil {
+		return nil, e
+	}
+	delete(emb, "offset")
+	l := new(yadisk.LastUploadedResourceList)
+	return l, convert(emb, l)
+}
+
+func (d *Disk) files(mediaTypes string) []*node {
+	var items []*node
+	for k, n := range d.nodes {
+		if n.typ != typeFile || !within(k, diskRoot) {
+			continue
+		}
+		if mediaTypes != "" && !containsItem(mediaTypes, mediaType(k)) {
+			continue
+		}
+		items = append(items, n)
+	}
+	return items
+}
+
+func (d *Disk) resolveDisk(p string) (string, error) {
+	key, e := d.resolve(p)
+	if e != nil {
+		return "", e
+	}
+	if !within(key, diskRoot) {
+		return "", newError(yadisk.ErrorIDFieldValidation)
+	}
+	return key, nil
+}
+
+// resolveTarget resolves the target of a write, the application folder is created on the first write to app:/.
+func (d *Disk) resolveTarget(p string) (string, error) {
+	key, e := d.resolveDisk(p)
+	if e != nil {
+		return "", e
+	}
+	if strings.HasPrefix(p, "app:") {
+		d.mkdirAll("disk:" + d.appFolder())
+	}
+	return key, nil
+}
+
+func (d *Disk) lookupDisk(p string) (string, *node, error) {
+	key, e := d.resolveDisk(p)
+	if e != nil {
+		return "", nil, e
+	}
+	n, ok := d.nodes[key]
+	if !ok {
+		return key, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	return key, n, nil
+}
+
+func (d *Disk) checkParent(p string) error {
+	parent, ok := d.nodes[parentOf(p)]
+	if !ok || parent.typ != typeDir {
+		return newError(yadisk.ErrorIDPathDoesntExist)
+	}
+	return nil
+}
+
+// mkdirAll creates the missing folders on the way to p, p included.
+func (d *Disk) mkdirAll(p string) {
+	if _, ok := d.nodes[p]; ok {
+		return
+	}
+	d.mkdirAll(parentOf(p))
+	d.nodes[p] = d.newNode(p, typeDir)
+}
+
+func (d *Disk) rename(from string, to string) {
+	for _, k := range d.subtree(from) {
+		n := d.nodes[k]
+		delete(d.nodes, k)
+		n.path = rebase(k, from, to)
+		n.revision = d.bump()
+		d.nodes[n.path] = n
+	}
+}
+
+func (d *Disk) copyTree(from string, to string) error {
+	keys := d.subtree(from)
+	var size int64
+	for _, k := range keys {
+		size += int64(len(d.nodes[k].data))
+	}
+	if d.usedSpace()+size > d.opts.TotalSpace {
+		return newError(yadisk.ErrorIDStorageQuotaExhausted)
+	}
+	for _, k := range keys {
+		n := d.nodes[k]
+		c := d.newNode(rebase(k, from, to), n.typ)
+		if n.typ == typeFile {
+			d.setData(c, n.data)
+		}
+		c.props = copyProps(n.props)
+		d.nodes[c.path] = c
+	}
+	return nil
+}
+
+func copyProps(props map[string]interface{}) map[string]interface{} {
+	if props == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		c[k] = v
+	}
+	return c
+}
+
+func containsItem(list string, item string) bool {
+	for _, s := range strings.Split(list, ",") {
+		if strings.TrimSpace(s) == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+package yadisktest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+// Server is a local HTTP stand-in for the cloud-api REST endpoints backed by a Disk.
+//
+// It serves the /v1/disk/... routes called by the SDK as well as the upload and download hrefs it hands out,
+// so the SDK or any other HTTP client can be tested end to end offline.
+type Server struct {
+	*httptest.Server
+	// Disk holding the state served.
+	Disk *Disk
+	// Token required in the Authorization header, any token is accepted if empty.
+	Token string
+
+	mu     sync.Mutex
+	faults []*Fault
+}
+
+// Fault is a failure injected into the requests it matches.
+type Fault struct {
+	// HTTP method to match, any if empty.
+	Method string
+	// Prefix of the URL path to match, such as "/v1/disk/resources/upload" or "/upload/", any if empty.
+	Path string
+	// Number of requests the fault applies to, all matching requests if zero.
+	Times int
+	// Delay before the request is handled or failed.
+	Latency time.Duration
+	// Status answered instead of handling the request, such as 429 or 503. Zero lets the request through.
+	Status int
+	// Value of the Retry-After header sent with Status.
+	RetryAfter time.Duration
+	// Close the connection without answering.
+	Drop bool
+}
+
+var faultErrorIDs = map[int]string{
+	http.StatusTooManyRequests:     yadisk.ErrorIDTooManyRequests,
+	http.StatusServiceUnavailable:  "ServiceUnavailableError",
+	http.StatusInternalServerError: "InternalServerError",
+}
+
+// NewServer starts a Server serving d, a new empty Disk if nil. Call Close when done.
+func NewServer(d *Disk) *Server {
+	if d == nil {
+		d = New(nil)
+	}
+	s := &Server{Disk: d}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	d.mu.Lock()
+	d.apiURL = s.URL
+	d.transferURL = s.URL
+	d.mu.Unlock()
+	return s
+}
+
+// InjectFault adds a fault. Faults are matched in the order they were added.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Client returns a client that sends requests for yadisk.BaseURL to the server,
+// so it can be passed to yadisk.NewYaDisk unchanged.
+func (s *Server) Client() *http.Client {
+	base, _ := url.Parse(yadisk.BaseURL)
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{from: base.Host, to: target, next: s.Server.Client().Transport}}
+}
+
+type rewriteTransport struct {
+	from string
+	to   *url.URL
+	next http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == t.from {
+		r := new(http.Request)
+		*r = *req
+		u := *req.URL
+		u.Scheme, u.Host = t.to.Scheme, t.to.Host
+		r.URL = &u
+		r.Host = ""
+		req = r
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		c := *f
+		return &c
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f := s.fault(r); f != nil {
+		if f.Latency > 0 {
+			select {
+			case <-time.After(f.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if f.Drop {
+			if hj, ok := w.(http.Hijacker); ok {
+				if conn, _, e := hj.Hijack(); e == nil {
+					_ = conn.Close()
+					return
+				}
+			}
+			panic(http.ErrAbortHandler)
+		}
+		if f.Status != 0 {
+			if f.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter/time.Second)))
+			}
+			errorID, ok := faultErrorIDs[f.Status]
+			if !ok {
+				errorID = strconv.Itoa(f.Status)
+			}
+			writeJSON(w, f.Status, &yadisk.Error{ErrorID: errorID, Description: http.StatusText(f.Status)})
+			return
+		}
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		s.Disk.serveTransfer(w, r)
+		return
+	}
+	if s.Token != "" && r.Header.Get("Authorization") != "OAuth "+s.Token {
+		writeError(w, newError(yadisk.ErrorIDUnauthorized))
+		return
+	}
+	s.serveAPI(w, r)
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	d := s.Disk
+	q := query(r.URL.Query())
+	route := r.Method + " " + strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/disk"), "/")
+
+	var out interface{}
+	var e error
+	switch {
+	case route == "GET ":
+		out, e = d.GetDisk(q.fields())
+	case route == "GET /resources":
+		out, e = d.GetResource(q.Get("path"), q.fields(), q.int("limit"), q.int("offset"), q.bool("preview_crop"), q.Get("preview_size"), q.Get("sort"))
+	case route == "PUT /resources":
+		out, e = d.CreateResource(q.Get("path"), q.fields())
+	case route == "PATCH /resources":
+		body := new(yadisk.ResourcePatch)
+		data, _ := ioutil.ReadAll(r.Body)
+		if json.Unmarshal(data, body) != nil {
+			e = newError(yadisk.ErrorIDFieldValidation)
+			break
+		}
+		out, e = d.UpdateResource(q.Get("path"), q.fields(), body)
+	case route == "DELETE /resources":
+		out, e = d.DeleteResource(q.Get("path"), q.fields(), q.bool("force_async"), q.Get("md5"), q.bool("permanently"))
+	case route == "POST /resources/copy":
+		out, e = d.CopyResource(q.Get("from"), q.Get("path"), q.fields(), q.bool("force_async"), q.bool("overwrite"))
+	case route == "POST /resources/move":
+		out, e = d.MoveResource(q.Get("from"), q.Get("path"), q.fields(), q.bool("force_async"), q.bool("overwrite"))
+	case route == "GET /resources/download":
+		out, e = d.GetResourceDownloadLink(q.Get("path"), q.fields())
+	case route == "GET /resources/files":
+		out, e = d.GetFlatFilesList(q.fields(), q.int("limit"), q.Get("media_type"), q.int("offset"), q.bool("preview_crop"), q.Get("preview_size"), q.Get("sort"))
+	case route == "GET /resources/last-uploaded":
+		out, e = d.GetLastUploadedFilesList(q.fields(), q.int("limit"), q.Get("media_type"), q.bool("preview_crop"), q.Get("preview_size"))
+	case route == "GET /resources/public":
+		out, e = d.ListPublicResources(q.fields(), q.int("limit"), q.int("offset"), q.bool("preview_crop"), q.Get("preview_size"), q.Get("type"))
+	case route == "PUT /resources/publish":
+		out, e = d.PublishResource(q.Get("path"), q.fields())
+	case route == "PUT /resources/unpublish":
+		out, e = d.UnpublishResource(q.Get("path"), q.fields())
+	case route == "GET /resources/upload":
+		out, e = d.GetResourceUploadLink(q.Get("path"), q.fields(), q.bool("overwrite"))
+	case route == "POST /resources/upload":
+		out, e = d.UploadExternalResource(q.Get("path"), q.Get("url"), q.bool("disable_redirects"), q.fields())
+	case route == "GET /trash/resources":
+		out, e = d.GetTrashResource(q.Get("path"), q.fields(), q.int("limit"), q.int("offset"), q.bool("preview_crop"), q.Get("preview_size"), q.Get("sort"))
+	case route == "DELETE /trash/resources":
+		out, e = d.ClearTrash(q.fields(), q.bool("force_async"), q.Get("path"))
+	case route == "PUT /trash/resources/restore":
+		out, e = d.RestoreFromTrash(q.Get("path"), q.fields(), q.bool("force_async"), q.Get("name"), q.bool("overwrite"))
+	case route == "GET /public/resources":
+		out, e = d.GetPublicResource(q.Get("public_key"), q.fields(), q.int("limit"), q.int("offset"), q.Get("path"), q.bool("preview_crop"), q.Get("preview_size"), q.Get("sort"))
+	case route == "GET /public/resources/download":
+		out, e = d.GetPublicResourceDownloadLink(q.Get("public_key"), q.fields(), q.Get("path"))
+	case route == "POST /public/resources/save-to-disk":
+		out, e = d.SaveToDiskPublicResource(q.Get("public_key"), q.fields(), q.bool("force_async"), q.Get("name"), q.Get("path"), q.Get("save_path"))
+	case strings.HasPrefix(route, "GET /operations/"):
+		out, e = d.GetOperationStatus(strings.TrimPrefix(route, "GET /operations/"), q.fields())
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, &yadisk.Error{ErrorID: "MethodNotAllowedError", Description: "Method Not Allowed"})
+		return
+	}
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+	writeJSON(w, status(r.Method, route, out), project(out, q.fields()))
+}
+
+// status returns the success status the real service answers a call with.
+func status(method string, route string, out interface{}) int {
+	link, ok := out.(*yadisk.Link)
+	switch {
+	case !ok:
+		return http.StatusOK
+	case link == nil || *link == (yadisk.Link{}):
+		return http.StatusNoContent
+	case strings.Contains(link.Href, "/operations/"):
+		return http.StatusAccepted
+	case method != http.MethodGet && !strings.Contains(route, "publish"):
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func writeError(w http.ResponseWriter, e error) {
+	err, ok := e.(*yadisk.Error)
+	if !ok {
+		err = &yadisk.Error{ErrorID: "InternalServerError", Description: e.Error()}
+	}
+	writeJSON(w, StatusCode(e), err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// project keeps only the requested fields of v, as the fields parameter of the API does.
+// Nested fields are separated by dots and applied to every element of arrays.
+func project(v interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+	data, e := json.Marshal(v)
+	if e != nil {
+		return v
+	}
+	var doc interface{}
+	if json.Unmarshal(data, &doc) != nil {
+		return v
+	}
+	out := interface{}(nil)
+	for _, f := range fields {
+		out = merge(out, pick(doc, strings.Split(f, ".")))
+	}
+	return out
+}
+
+func pick(doc interface{}, keys []string) interface{} {
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		v, ok := t[keys[0]]
+		if !ok {
+			return nil
+		}
+		if len(keys) > 1 {
+			v = pick(v, keys[1:])
+			if v == nil {
+				return nil
+			}
+		}
+		return map[string]interface{}{keys[0]: v}
+	case []interface{}:
+		items := make([]interface{}, len(t))
+		for i, item := range t {
+			items[i] = pick(item, keys)
+		}
+		return items
+	}
+	return nil
+}
+
+func merge(a interface{}, b interface{}) interface{} {
+	switch bt := b.(type) {
+	case map[string]interface{}:
+		at, ok := a.(map[string]interface{})
+		if !ok {
+			return bt
+		}
+		for k, v := range bt {
+			at[k] = merge(at[k], v)
+		}
+		return at
+	case []interface{}:
+		at, ok := a.([]interface{})
+		if !ok || len(at) != len(bt) {
+			return bt
+		}
+		for i := range bt {
+			at[i] = merge(at[i], bt[i])
+		}
+		return at
+	case nil:
+		return a
+	}
+	return b
+}
+
+type query url.Values
+
+func (q query) Get(key string) string {
+	return url.Values(q).Get(key)
+}
+
+func (q query) int(key string) int {
+	i, _ := strconv.Atoi(q.Get(key))
+	return i
+}
+
+func (q query) bool(key string) bool {
+	b, _ := strconv.ParseBool(q.Get(key))
+	return b
+}
+
+func (q query) fields() []string {
+	if f := q.Get("fields"); f != "" {
+		return strings.Split(f, ",")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package yadisktest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+func newTestServer(t *testing.T) (*Server, yadisk.YaDisk) {
+	s := NewServer(nil)
+	s.Token = "token"
+	yaDisk, err := yadisk.NewYaDisk(context.Background(), s.Client(), &yadisk.Token{AccessToken: "token"})
+	if err != nil {
+		t.Fatalf("NewYaDisk() error = %v", err)
+	}
+	return s, yaDisk
+}
+
+func TestServer_sdk(t *testing.T) {
+	s, yaDisk := newTestServer(t)
+	defer s.Close()
+
+	if _, err := yaDisk.CreateResource("/dir", nil); err != nil {
+		t.Fatalf("CreateResource() error = %v", err)
+	}
+	if _, err := yaDisk.CreateResource("/dir", nil); !yadisk.IsErrorID(err, yadisk.ErrorIDPathPointsToExistentDir) {
+		t.Fatalf("CreateResource() error = %v, want %v", err, yadisk.ErrorIDPathPointsToExistentDir)
+	}
+	link, err := yaDisk.GetResourceUploadLink("/dir/a.txt", nil, false)
+	if err != nil {
+		t.Fatalf("GetResourceUploadLink() error = %v", err)
+	}
+	if _, err := yaDisk.PerformUpload(link, bytes.NewBufferString("hello")); err != nil {
+		t.Fatalf("PerformUpload() error = %v", err)
+	}
+	r, err := yaDisk.GetResource("/dir", []string{"path", "_embedded.items.name"}, 0, 0, false, "", "")
+	if err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	if r.Path != "disk:/dir" || r.Type != "" || len(r.Embedded.Items) != 1 || r.Embedded.Items[0].Name != "a.txt" || r.Embedded.Items[0].Size != 0 {
+		t.Errorf("GetResource() = %+v", r)
+	}
+	if l, err := yaDisk.DeleteResource("/dir/a.txt", nil, false, "", false); err != nil || l != nil {
+		t.Errorf("DeleteResource() = %v, %v, want 204", l, err)
+	}
+	trash, err := yaDisk.GetTrashResource("trash:/", nil, 0, 0, false, "", "")
+	if err != nil || len(trash.Embedded.Items) != 1 || trash.Embedded.Items[0].OriginPath != "disk:/dir/a.txt" {
+		t.Errorf("GetTrashResource() = %+v, %v", trash, err)
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	s, _ := newTestServer(t)
+	defer s.Close()
+
+	yaDisk, _ := yadisk.NewYaDisk(context.Background(), s.Client(), &yadisk.Token{AccessToken: "other"})
+	if _, err := yaDisk.GetDisk(nil); !yadisk.IsErrorID(err, yadisk.ErrorIDUnauthorized) {
+		t.Errorf("GetDisk() error = %v, want %v", err, yadisk.ErrorIDUnauthorized)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	s, yaDisk := newTestServer(t)
+	defer s.Close()
+
+	tests := []struct {
+		name    string
+		fault   Fault
+		wantErr bool
+	}{
+		{"too_many_requests", Fault{Path: "/v1/disk", Status: http.StatusTooManyRequests, Times: 1}, true},
+		{"passed", Fault{Method: http.MethodPost, Status: http.StatusServiceUnavailable}, false},
+		{"dropped", Fault{Drop: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.InjectFault(tt.fault)
+			defer s.ClearFaults()
+			if _, err := yaDisk.GetDisk(nil); (err != nil) != tt.wantErr {
+				t.Errorf("GetDisk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := yaDisk.GetDisk(nil); err != nil && tt.fault.Times == 1 {
+				t.Errorf("GetDisk() after fault error = %v", err)
+			}
+		})
+	}
+	s.InjectFault(Fault{Status: http.StatusTooManyRequests})
+	if _, err := yaDisk.GetDisk(nil); !yadisk.IsErrorID(err, yadisk.ErrorIDTooManyRequests) {
+		t.Errorf("GetDisk() error = %v, want %v", err, yadisk.ErrorIDTooManyRequests)
+	}
+}
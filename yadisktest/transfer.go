@@ -0,0 +1,200 @@
+package yadisktest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// HTTPClient returns a client that serves the upload and download hrefs handed out by d in memory.
+// Requests to any other URL go through http.DefaultTransport.
+func (d *Disk) HTTPClient() *http.Client {
+	return &http.Client{Transport: &transport{disk: d}}
+}
+
+type transport struct {
+	disk *Disk
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.disk.mu.Lock()
+	base := t.disk.transferURL
+	t.disk.mu.Unlock()
+	if !strings.HasPrefix(req.URL.String(), base+"/") {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	rec := httptest.NewRecorder()
+	t.disk.serveTransfer(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func (d *Disk) downloadHref(p string, publicKey string) string {
+	values := url.Values{}
+	values.Add("path", p)
+	if publicKey != "" {
+		values.Add("public_key", publicKey)
+	}
+	return d.transferURL + "/download/?" + values.Encode()
+}
+
+// serveTransfer handles PUT requests to upload hrefs, with or without Content-Range,
+// and GET requests to download hrefs, with Range support. Folders are downloaded as zip archives.
+func (d *Disk) serveTransfer(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.Contains(r.URL.Path, "/upload/") && r.Method == http.MethodPut:
+		d.serveUpload(w, r)
+	case strings.HasSuffix(r.URL.Path, "/download/") && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		d.serveDownload(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (d *Disk) serveUpload(w http.ResponseWriter, r *http.Request) {
+	data, e := ioutil.ReadAll(r.Body)
+	if e != nil {
+		http.Error(w, e.Error(), http.StatusBadRequest)
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := path.Base(r.URL.Path)
+	u, ok := d.uploads[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if cr := r.Header.Get("Content-Range"); cr != "" {
+		var start, end, total int64
+		if _, e := fmt.Sscanf(cr, "bytes %d-%d/%d", &start, &end, &total); e != nil || end < start || end >= total || end-start+1 != int64(len(data)) {
+			http.Error(w, "bad Content-Range", http.StatusBadRequest)
+			return
+		}
+		if int64(len(u.data)) != total {
+			u.data = make([]byte, total)
+			u.chunks = make(map[int64]int64)
+		}
+		copy(u.data[start:], data)
+		u.chunks[start] = int64(len(data))
+		var received int64
+		for _, n := range u.chunks {
+			received += n
+		}
+		if received < total {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		data = u.data
+	}
+	status := d.commit(u, data)
+	if status == http.StatusCreated {
+		delete(d.uploads, id)
+	}
+	w.WriteHeader(status)
+}
+
+func (d *Disk) serveDownload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	d.mu.Lock()
+	var n *node
+	var e error
+	if key := q.Get("public_key"); key != "" {
+		_, n, e = d.lookupPublic(key, q.Get("path"))
+	} else {
+		_, n, e = d.lookupDisk(q.Get("path"))
+	}
+	if e != nil {
+		d.mu.Unlock()
+		http.NotFound(w, r)
+		return
+	}
+	name, modified, data := baseOf(n.path), n.modified, n.data
+	if n.typ == typeDir {
+		name += ".zip"
+		data, e = d.zip(n.path)
+	}
+	d.mu.Unlock()
+	if e != nil {
+		http.Error(w, e.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	http.ServeContent(w, r, name, modified, bytes.NewReader(data))
+}
+
+func (d *Disk) zip(p string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, k := range d.subtree(p) {
+		n := d.nodes[k]
+		if n.typ != typeFile {
+			continue
+		}
+		f, e := zw.Create(strings.TrimPrefix(rebase(k, p, "/"+baseOf(p)), "/"))
+		if e != nil {
+			return nil, e
+		}
+		if _, e := f.Write(n.data); e != nil {
+			return nil, e
+		}
+	}
+	if e := zw.Close(); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+func checksums(data []byte) (string, string) {
+	m := md5.Sum(data)
+	s := sha256.Sum256(data)
+	return hex.EncodeToString(m[:]), hex.EncodeToString(s[:])
+}
+
+func mimeType(p string) string {
+	if t := mime.TypeByExtension(path.Ext(p)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+var mediaTypes = map[string]string{
+	".aac": "audio", ".flac": "audio", ".mp3": "audio", ".ogg": "audio", ".wav": "audio",
+	".bak":  "backup",
+	".epub": "book", ".fb2": "book", ".mobi": "book",
+	".7z": "compressed", ".gz": "compressed", ".rar": "compressed", ".tar": "compressed", ".zip": "compressed",
+	".db": "data", ".sql": "data", ".sqlite": "data",
+	".c": "development", ".go": "development", ".java": "development", ".js": "development", ".py": "development",
+	".dmg": "diskimage", ".img": "diskimage", ".iso": "diskimage",
+	".doc": "document", ".docx": "document", ".odt": "document", ".pdf": "document", ".rtf": "document",
+	".gpg": "encoded", ".pgp": "encoded",
+	".apk": "executable", ".exe": "executable", ".msi": "executable",
+	".swf": "flash",
+	".otf": "font", ".ttf": "font", ".woff": "font",
+	".bmp": "image", ".gif": "image", ".jpeg": "image", ".jpg": "image", ".png": "image", ".svg": "image",
+	".cfg": "settings", ".conf": "settings", ".ini": "settings",
+	".csv": "spreadsheet", ".ods": "spreadsheet", ".xls": "spreadsheet", ".xlsx": "spreadsheet",
+	".log": "text", ".md": "text", ".txt": "text",
+	".avi": "video", ".mkv": "video", ".mov": "video", ".mp4": "video",
+	".css": "web", ".htm": "web", ".html": "web",
+}
+
+func mediaType(p string) string {
+	if t, ok := mediaTypes[strings.ToLower(path.Ext(p))]; ok {
+		return t
+	}
+	return "unknown"
+}
@@ -0,0 +1,131 @@
+package yadisktest
+
+import (
+	"time"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+// Empty trash.
+//
+// If the path parameter is not specified or points to the root of the Recycle Bin,
+// the recycle bin will be completely cleared, otherwise only the resource pointed to by the path will be deleted from the Recycle Bin.
+func (d *Disk) ClearTrash(fields []string, forceAsync bool, path string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := trashRoot
+	if path != "" {
+		var e error
+		key, _, e = d.lookupTrash(path)
+		if e != nil {
+			return nil, e
+		}
+	}
+	async := forceAsync || len(d.children(key)) > 0
+	for _, n := range d.children(key) {
+		d.removeTree(n.path)
+	}
+	if key != trashRoot {
+		d.removeTree(key)
+	}
+	d.bump()
+	if async {
+		id, _ := d.newOperation()
+		return d.operationLink(id), nil
+	}
+	return new(yadisk.Link), nil
+}
+
+// Get the contents of the Trash.
+func (d *Disk) GetTrashResource(path string, fields []string, limit int, offset int, previewCrop bool, previewSize string, sort string) (*yadisk.TrashResource, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if path == "" {
+		path = trashRoot
+	}
+	key, n, e := d.lookupTrash(path)
+	if e != nil {
+		return nil, e
+	}
+	doc := d.doc(n)
+	if n.typ == typeDir {
+		doc["_embedded"], e = d.embedded(key, d.children(key), limit, offset, sort)
+		if e != nil {
+			return nil, e
+		}
+	}
+	r := new(yadisk.TrashResource)
+	return r, convert(doc, r)
+}
+
+// Recover Resource from Trash.
+//
+// Missing folders on the way to the original path are created again.
+func (d *Disk) RestoreFromTrash(path string, fields []string, forceAsync bool, name string, overwrite bool) (*yadisk.Link, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, n, e := d.lookupTrash(path)
+	if e != nil {
+		return nil, e
+	}
+	if key == trashRoot {
+		return nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	dst := n.originPath
+	if dst == "" {
+		dst = diskRoot + baseOf(key)
+	}
+	if name != "" {
+		dst = parentOf(dst) + "/" + name
+		dst, _ = d.resolve(dst)
+	}
+	if _, ok := d.nodes[dst]; ok {
+		if !overwrite {
+			return nil, newError(yadisk.ErrorIDResourceAlreadyExists)
+		}
+		d.removeTree(dst)
+	}
+	d.mkdirAll(parentOf(dst))
+	d.rename(key, dst)
+	n.originPath = ""
+	n.deleted = time.Time{}
+	return d.result(forceAsync, dst), nil
+}
+
+func (d *Disk) lookupTrash(p string) (string, *node, error) {
+	key, e := d.resolve(p)
+	if e != nil {
+		return "", nil, e
+	}
+	if !within(key, trashRoot) {
+		return "", nil, newError(yadisk.ErrorIDFieldValidation)
+	}
+	n, ok := d.nodes[key]
+	if !ok {
+		return key, nil, newError(yadisk.ErrorIDNotFound)
+	}
+	return key, n, nil
+}
+
+// trash moves the subtree p into the trash and records where it came from.
+func (d *Disk) trash(p string) {
+	name := baseOf(p)
+	key := trashRoot + name
+	if _, ok := d.nodes[key]; ok {
+		key += "_" + d.hash("trash")[:8]
+	}
+	n := d.nodes[p]
+	for _, k := range d.subtree(p) {
+		if c := d.nodes[k]; c.publicKey != "" {
+			delete(d.public, c.publicKey)
+			delete(d.public, c.publicURL)
+			c.publicKey, c.publicURL = "", ""
+		}
+	}
+	d.rename(p, key)
+	n.originPath = p
+	n.deleted = d.now()
+}
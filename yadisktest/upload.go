@@ -0,0 +1,184 @@
+package yadisktest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	yadisk "github.com/nikitaksv/yandex-disk-sdk-go"
+)
+
+type upload struct {
+	path      string
+	overwrite bool
+	op        *operation
+	data      []byte
+	// Length of the chunk received at each offset, a chunk sent again is counted once.
+	chunks map[int64]int64
+}
+
+// Get file upload link.
+//
+// The returned href accepts a PUT of the file data through HTTPClient or PerformUpload.
+func (d *Disk) GetResourceUploadLink(path string, fields []string, overwrite bool) (*yadisk.ResourceUploadLink, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, e := d.checkUploadTarget(path, overwrite)
+	if e != nil {
+		return nil, e
+	}
+	id, op := d.newOperation()
+	op.pending = true
+	d.uploads[id] = &upload{path: key, overwrite: overwrite, op: op}
+	return &yadisk.ResourceUploadLink{
+		OperationID: id,
+		Href:        d.transferURL + "/upload/" + id,
+		Method:      http.MethodPut,
+	}, nil
+}
+
+// Upload file to Disk by URL.
+//
+// The external URL is fetched with Options.HTTPClient before the call returns,
+// the returned operation fails if the fetch does.
+func (d *Disk) UploadExternalResource(path string, externalURL string, disableRedirects bool, fields []string) (*yadisk.Link, error) {
+	d.mu.Lock()
+	key, e := d.checkUploadTarget(path, false)
+	if e != nil {
+		d.mu.Unlock()
+		return nil, e
+	}
+	id, op := d.newOperation()
+	op.pending = true
+	d.mu.Unlock()
+
+	data, fetchErr := d.fetch(externalURL, disableRedirects)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	op.pending = false
+	if fetchErr != nil || d.commit(&upload{path: key, op: op}, data) != http.StatusCreated {
+		op.failed = true
+	}
+	return d.operationLink(id), nil
+}
+
+// This custom method to upload data by link.
+func (d *Disk) PerformUpload(ur *yadisk.ResourceUploadLink, data *bytes.Buffer) (*yadisk.PerformUpload, error) {
+	var body []byte
+	if data != nil {
+		body = data.Bytes()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := strings.TrimPrefix(ur.Href, d.transferURL+"/upload/")
+	u, ok := d.uploads[id]
+	if !ok {
+		return nil, uploadError(http.StatusNotFound)
+	}
+	if status := d.commit(u, body); status != http.StatusCreated {
+		return nil, uploadError(status)
+	}
+	delete(d.uploads, id)
+	return &yadisk.PerformUpload{}, nil
+}
+
+// This custom method to upload data by link.
+//
+// The data is stored at once, partSize is only validated.
+func (d *Disk) PerformPartialUpload(ur *yadisk.ResourceUploadLink, data *bytes.Buffer, partSize int64) (*yadisk.PerformUpload, error) {
+	if data == nil {
+		return nil, fmt.Errorf("data can not be nil")
+	}
+	if partSize > int64(data.Len()) {
+		return nil, fmt.Errorf("partSize can not be more than data.Len()")
+	}
+	return d.PerformUpload(ur, data)
+}
+
+func (d *Disk) checkUploadTarget(p string, overwrite bool) (string, error) {
+	key, e := d.resolveTarget(p)
+	if e != nil {
+		return "", e
+	}
+	if e := d.checkParent(key); e != nil {
+		return "", e
+	}
+	if n, ok := d.nodes[key]; ok {
+		if n.typ == typeDir {
+			return "", newError(yadisk.ErrorIDPathPointsToExistentDir)
+		}
+		if !overwrite {
+			return "", newError(yadisk.ErrorIDResourceAlreadyExists)
+		}
+	}
+	return key, nil
+}
+
+// commit stores the uploaded data and returns the HTTP status the upload href answers with.
+func (d *Disk) commit(u *upload, data []byte) int {
+	size := int64(len(data))
+	if size > d.opts.MaxFileSize {
+		return http.StatusRequestEntityTooLarge
+	}
+	n, exists := d.nodes[u.path]
+	var old int64
+	if exists {
+		if n.typ == typeDir || !u.overwrite {
+			return http.StatusConflict
+		}
+		old = int64(len(n.data))
+	}
+	if d.usedSpace()-old+size > d.opts.TotalSpace {
+		return http.StatusInsufficientStorage
+	}
+	if d.checkParent(u.path) != nil {
+		return http.StatusConflict
+	}
+	if !exists {
+		n = d.newNode(u.path, typeFile)
+		d.nodes[u.path] = n
+	}
+	d.setData(n, append([]byte(nil), data...))
+	u.op.pending = false
+	return http.StatusCreated
+}
+
+func (d *Disk) fetch(externalURL string, disableRedirects bool) ([]byte, error) {
+	client := *d.opts.HTTPClient
+	if disableRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	resp, e := client.Get(externalURL)
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", externalURL, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// WriteFile stores data at p, creating missing folders on the way and replacing an existing file.
+// It is a shortcut for seeding a Disk in tests.
+func (d *Disk) WriteFile(p string, data []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, e := d.resolveTarget(p)
+	if e != nil {
+		return e
+	}
+	d.mkdirAll(parentOf(key))
+	if status := d.commit(&upload{path: key, overwrite: true, op: new(operation)}, data); status != http.StatusCreated {
+		return uploadError(status)
+	}
+	return nil
+}